@@ -1,8 +1,14 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	v1 "github.com/gurodrigues-dev/notifier-app/cmd/api/notify-api/routes/v1"
@@ -10,6 +16,8 @@ import (
 	"github.com/gurodrigues-dev/notifier-app/internal/domain/middleware"
 	"github.com/gurodrigues-dev/notifier-app/internal/infra"
 	"github.com/gurodrigues-dev/notifier-app/internal/infra/setup"
+	"github.com/gurodrigues-dev/notifier-app/internal/usecase"
+	"github.com/gurodrigues-dev/notifier-app/internal/value"
 	"github.com/spf13/viper"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
@@ -29,14 +37,86 @@ func main() {
 	setup.Postgres()
 	setup.Repositories()
 	setup.Email()
+	setup.Webhook()
+	setup.Providers()
 	setup.Queue()
 	setup.Metrics()
 
 	setup.Finish()
 
-	notifyApiPort := viper.GetString("NOTIFY_APP_PORT")
-	notifyApi := setupNotifyApi()
-	notifyApi.Run(fmt.Sprintf(":%s", notifyApiPort))
+	timeouts := value.GetHTTPTimeouts()
+	server := &http.Server{
+		Addr:         fmt.Sprintf(":%s", viper.GetString("NOTIFY_APP_PORT")),
+		Handler:      setupNotifyApi(),
+		ReadTimeout:  timeouts.Read,
+		WriteTimeout: timeouts.Write,
+		IdleTimeout:  timeouts.Idle,
+	}
+
+	relayCtx, stopRelay := context.WithCancel(context.Background())
+	relayDone := make(chan struct{})
+	go func() {
+		defer close(relayDone)
+		relayOutbox(relayCtx)
+	}()
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+
+	go func() {
+		infra.App.Logger.Infof(fmt.Sprintf("Notify API listening on %s", server.Addr))
+		err := server.ListenAndServe()
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			infra.App.Logger.Errorf(fmt.Sprintf("Notify API stopped: %v", err))
+			sigs <- syscall.SIGTERM
+		}
+	}()
+
+	sig := <-sigs
+	infra.App.Logger.Infof(fmt.Sprintf("Signal received: %s, shutting down gracefully...", sig))
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeouts.Shutdown)
+	defer cancel()
+
+	err := server.Shutdown(ctx)
+	if err != nil {
+		infra.App.Logger.Errorf(fmt.Sprintf("Error draining requests: %v", err))
+	}
+
+	stopRelay()
+	<-relayDone
+
+	setup.Close()
+	infra.App.Logger.Infof("Application stopped.")
+}
+
+// relayOutbox publishes the notifications stored in the outbox until ctx is
+// cancelled. A full batch is followed right away by the next one.
+func relayOutbox(ctx context.Context) {
+	config := value.GetOutbox()
+	usecase := usecase.NewRelayOutboxUsecase(
+		infra.App.Repositories.OutboxRepository,
+		infra.App.Repositories.NotificationRepository,
+		infra.App.Queue,
+		infra.App.Logger,
+	)
+
+	for {
+		sent, err := usecase.Relay(config.BatchSize, config.Lease)
+		if err != nil {
+			infra.App.Logger.Errorf(fmt.Sprintf("Error relaying outbox: %v", err))
+		}
+
+		if err == nil && sent == config.BatchSize && ctx.Err() == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(config.PollInterval):
+		}
+	}
 }
 
 func setupNotifyApi() *gin.Engine {
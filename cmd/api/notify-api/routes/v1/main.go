@@ -11,6 +11,7 @@ type Controllers struct {
 	Auth         *controller.AuthController
 	Notification *controller.NotificationController
 	Channel      *controller.ChannelController
+	DeadLetter   *controller.DeadLetterController
 }
 
 func NewControllers() *Controllers {
@@ -18,12 +19,17 @@ func NewControllers() *Controllers {
 		Notification: controller.NewNotificationController(infra.App.Logger),
 		Auth:         controller.NewAuthController(infra.App.Logger),
 		Channel:      controller.NewChannelController(infra.App.Logger),
+		DeadLetter:   controller.NewDeadLetterController(infra.App.Logger),
 	}
 }
 
 func (routes *Controllers) Routes(group *gin.RouterGroup, middleware *middleware.Middleware) {
 	group.POST("/notification", middleware.TokenMiddleware(), routes.Notification.CreateNotification)
+	group.POST("/notifications/batch", middleware.TokenMiddleware(), routes.Notification.CreateNotificationBatch)
 	group.GET("/notification/:id", middleware.AdminMiddleware(), routes.Notification.GetNotification)
+	group.GET("/notification/:id/status", middleware.TokenMiddleware(), routes.Notification.GetNotificationStatus)
+	group.DELETE("/notification/:id/schedule", middleware.TokenMiddleware(), routes.Notification.CancelScheduledNotification)
+	group.PATCH("/notification/:id/schedule", middleware.TokenMiddleware(), routes.Notification.RescheduleNotification)
 
 	group.POST("/token", middleware.AdminMiddleware(), routes.Auth.CreateToken)
 	group.GET("/token/:user", middleware.AdminMiddleware(), routes.Auth.GetToken)
@@ -34,4 +40,8 @@ func (routes *Controllers) Routes(group *gin.RouterGroup, middleware *middleware
 	group.DELETE("/channel/:id", middleware.TokenMiddleware(), routes.Channel.DeleteById)
 	group.GET("/group/:group", middleware.TokenMiddleware(), routes.Channel.FindByGroup)
 	group.GET("/platform/:platform", middleware.TokenMiddleware(), routes.Channel.FindByPlatform)
+
+	group.GET("/dead-letter", middleware.AdminMiddleware(), routes.DeadLetter.ListDeadLetters)
+	group.GET("/dead-letter/:id", middleware.AdminMiddleware(), routes.DeadLetter.GetDeadLetter)
+	group.POST("/dead-letter/:id/redrive", middleware.AdminMiddleware(), routes.DeadLetter.RedriveDeadLetter)
 }
@@ -5,12 +5,14 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 
 	"github.com/gurodrigues-dev/notifier-app/internal/infra"
+	"github.com/gurodrigues-dev/notifier-app/internal/infra/contracts"
 	"github.com/gurodrigues-dev/notifier-app/internal/infra/setup"
-	"github.com/gurodrigues-dev/notifier-app/internal/infra/webhook"
 	"github.com/gurodrigues-dev/notifier-app/internal/usecase"
+	"github.com/gurodrigues-dev/notifier-app/internal/value"
 	"github.com/spf13/viper"
 )
 
@@ -21,6 +23,8 @@ func main() {
 	setup.Postgres()
 	setup.Repositories()
 	setup.Email()
+	setup.Webhook()
+	setup.Providers()
 	setup.Queue()
 	setup.Metrics()
 
@@ -40,30 +44,59 @@ func main() {
 
 	handler(ctx)
 
-	<-ctx.Done()
+	setup.Close()
 	infra.App.Logger.Infof("Application stopped.")
 }
 
+// handler consumes every topic until ctx is cancelled and returns once all
+// consumers have drained. Notifications are dispatched from the main and
+// retry topics, and the dead-letter topic is stored for admins to re-drive.
 func handler(ctx context.Context) {
-	usecase := usecase.NewDispatcherUsecase(
+	dispatcher := usecase.NewDispatcherUsecase(
 		infra.App.Repositories.NotificationRepository,
-		infra.App.Email,
-		&webhook.DefaultClient{},
+		infra.App.Repositories.DeadLetterRepository,
+		infra.App.Repositories.DeliveryRepository,
+		infra.App.Providers,
+		infra.App.Queue,
 		infra.App.Logger,
 	)
+	storeDeadLetter := usecase.NewStoreDeadLetterUsecase(
+		infra.App.Repositories.DeadLetterRepository,
+		infra.App.Logger,
+	)
+
+	handlers := map[string]func(ctx context.Context, message contracts.Message) error{
+		viper.GetString("KAFKA_TOPIC"): dispatcher.Execute,
+		value.GetDeadLetterTopic():     storeDeadLetter.Execute,
+	}
+	for _, tier := range value.GetRetryTiers() {
+		handlers[tier.Topic] = dispatcher.Execute
+	}
+
+	var wg sync.WaitGroup
 
 	infra.App.Logger.Infof("Starting Kafka dispatcher...")
-	err := infra.App.Queue.Consumer(
-		viper.GetString("KAFKA_TOPIC"),
-		viper.GetString("KAFKA_GROUP"),
-		func(message string) {
-			err := usecase.Execute(message)
+	for topic, execute := range handlers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := infra.App.Queue.Consumer(
+				ctx,
+				topic,
+				viper.GetString("KAFKA_GROUP"),
+				func(ctx context.Context, message contracts.Message) error {
+					err := execute(ctx, message)
+					if err != nil {
+						infra.App.Logger.Errorf(fmt.Sprintf("Consume message error: %v", err))
+					}
+					return err
+				},
+			)
 			if err != nil {
-				infra.App.Logger.Errorf(fmt.Sprintf("Consume message error: %v", err))
+				infra.App.Logger.Errorf(fmt.Sprintf("Error consuming %s: %v", topic, err))
 			}
-		},
-	)
-	if err != nil {
-		infra.App.Logger.Errorf(fmt.Sprintf("Error starting consumer: %v", err))
+		}()
 	}
+
+	wg.Wait()
 }
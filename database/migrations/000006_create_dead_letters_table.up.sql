@@ -0,0 +1,15 @@
+BEGIN;
+
+CREATE TABLE dead_letters (
+    id SERIAL PRIMARY KEY,
+    uuid VARCHAR(255) NOT NULL,
+    topic VARCHAR(255) NOT NULL,
+    payload TEXT NOT NULL,
+    errors TEXT[] NOT NULL DEFAULT '{}',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    received_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    failed_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    redriven_at TIMESTAMP WITH TIME ZONE
+);
+
+COMMIT;
@@ -0,0 +1,11 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_notifications_uuid;
+
+ALTER TABLE notifications
+DROP COLUMN IF EXISTS status,
+DROP COLUMN IF EXISTS retries,
+DROP COLUMN IF EXISTS created_at,
+DROP COLUMN IF EXISTS updated_at;
+
+COMMIT;
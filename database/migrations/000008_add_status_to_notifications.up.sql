@@ -0,0 +1,11 @@
+BEGIN;
+
+ALTER TABLE notifications
+ADD COLUMN status VARCHAR(50) NOT NULL DEFAULT 'accepted',
+ADD COLUMN retries INTEGER NOT NULL DEFAULT 0,
+ADD COLUMN created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+ADD COLUMN updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW();
+
+CREATE INDEX idx_notifications_uuid ON notifications (uuid);
+
+COMMIT;
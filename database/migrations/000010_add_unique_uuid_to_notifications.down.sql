@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP INDEX IF EXISTS unique_notifications_uuid;
+
+CREATE INDEX idx_notifications_uuid ON notifications (uuid);
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_notifications_uuid;
+
+CREATE UNIQUE INDEX unique_notifications_uuid ON notifications (uuid);
+
+COMMIT;
@@ -0,0 +1,11 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_outbox_pending_key;
+
+DROP INDEX IF EXISTS idx_outbox_pending;
+
+CREATE INDEX idx_outbox_pending ON outbox (id) WHERE sent_at IS NULL;
+
+ALTER TABLE outbox DROP COLUMN IF EXISTS available_at;
+
+COMMIT;
@@ -0,0 +1,11 @@
+BEGIN;
+
+ALTER TABLE outbox ADD COLUMN available_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW();
+
+DROP INDEX IF EXISTS idx_outbox_pending;
+
+CREATE INDEX idx_outbox_pending ON outbox (available_at, id) WHERE sent_at IS NULL;
+
+CREATE INDEX idx_outbox_pending_key ON outbox (key) WHERE sent_at IS NULL;
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE channels ADD COLUMN secret TEXT NOT NULL DEFAULT '';
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP INDEX IF EXISTS unique_pending_dead_letters;
+
+COMMIT;
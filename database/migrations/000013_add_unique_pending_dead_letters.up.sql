@@ -0,0 +1,5 @@
+BEGIN;
+
+CREATE UNIQUE INDEX unique_pending_dead_letters ON dead_letters (uuid, attempts) WHERE redriven_at IS NULL AND uuid <> '';
+
+COMMIT;
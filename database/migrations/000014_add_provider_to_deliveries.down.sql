@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE deliveries DROP COLUMN IF EXISTS provider;
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE deliveries ADD COLUMN provider VARCHAR(50) NOT NULL DEFAULT '';
+
+COMMIT;
@@ -293,6 +293,176 @@ const docTemplate = `{
                 }
             }
         },
+        "/dead-letter": {
+            "get": {
+                "description": "Lists the notifications sent to the dead-letter topic, newest first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dead-letter"
+                ],
+                "summary": "List dead letters",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 50, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Dead letters retrieved successfully",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.DeadLetter"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid pagination",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/dead-letter/{id}": {
+            "get": {
+                "description": "Retrieves a dead letter with its original payload, error chain and attempts.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dead-letter"
+                ],
+                "summary": "Get dead letter by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Dead letter ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Dead letter retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/entity.DeadLetter"
+                        }
+                    },
+                    "404": {
+                        "description": "Dead letter not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/dead-letter/{id}/redrive": {
+            "post": {
+                "description": "Sends a dead letter back to the main topic with its retries reset.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dead-letter"
+                ],
+                "summary": "Re-drive dead letter",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Dead letter ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Dead letter re-driven successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Dead letter not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Dead letter was already re-driven",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Dead letter payload is not a notification",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
         "/notification": {
             "post": {
                 "description": "Creates a new notification based on the provided notification data.",
@@ -305,27 +475,235 @@ const docTemplate = `{
                 "tags": [
                     "notification"
                 ],
-                "summary": "Create a new notification",
+                "summary": "Create a new notification",
+                "parameters": [
+                    {
+                        "description": "Notification request body",
+                        "name": "notification",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/value.NotificationInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Notification accepted",
+                        "schema": {
+                            "$ref": "#/definitions/value.NotificationAccepted"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "Notification status URL"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Notification already exists",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable entity",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/notification/{id}": {
+            "get": {
+                "description": "Retrieves a notification by its unique identifier.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notification"
+                ],
+                "summary": "Get notification by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Notification ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Notification retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/value.NotificationInput"
+                        }
+                    },
+                    "404": {
+                        "description": "Notification not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/notification/{id}/schedule": {
+            "delete": {
+                "description": "Cancels a notification scheduled with send_at before it is sent.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notification"
+                ],
+                "summary": "Cancel a scheduled notification",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Notification UUID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Notification cancelled",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Notification not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Notification is not scheduled",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Moves a notification scheduled with send_at to a new time before it is sent. The new time must be before the notification expires_at.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notification"
+                ],
+                "summary": "Reschedule a scheduled notification",
                 "parameters": [
                     {
-                        "description": "Notification request body",
-                        "name": "notification",
+                        "type": "string",
+                        "description": "Notification UUID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New send time",
+                        "name": "schedule",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/value.NotificationInput"
+                            "$ref": "#/definitions/value.RescheduleInput"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Notification sent successfully",
+                        "description": "Notification rescheduled",
                         "schema": {
-                            "type": "string"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Invalid request body",
+                        "description": "Invalid request body, send_at in the past or after expires_at",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Notification not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Notification is not scheduled",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -354,20 +732,20 @@ const docTemplate = `{
                 }
             }
         },
-        "/notification/{id}": {
+        "/notification/{id}/status": {
             "get": {
-                "description": "Retrieves a notification by its unique identifier.",
+                "description": "Retrieves the lifecycle of a notification and the delivery status of each of its channels.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "notification"
                 ],
-                "summary": "Get notification by ID",
+                "summary": "Get notification status by UUID",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Notification ID",
+                        "description": "Notification UUID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -375,9 +753,9 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Notification retrieved successfully",
+                        "description": "Notification status retrieved successfully",
                         "schema": {
-                            "$ref": "#/definitions/value.NotificationInput"
+                            "$ref": "#/definitions/value.NotificationStatusOutput"
                         }
                     },
                     "404": {
@@ -401,6 +779,67 @@ const docTemplate = `{
                 }
             }
         },
+        "/notifications/batch": {
+            "post": {
+                "description": "Creates many notifications at once, returning whether each one was accepted, a duplicate or invalid.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notification"
+                ],
+                "summary": "Create notifications in batch",
+                "parameters": [
+                    {
+                        "description": "Notification batch request body",
+                        "name": "notifications",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/value.NotificationBatchInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Result of each notification",
+                        "schema": {
+                            "$ref": "#/definitions/value.NotificationBatchOutput"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable entity",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
         "/token": {
             "post": {
                 "description": "Creates a new authentication token based on the provided user data.",
@@ -585,11 +1024,81 @@ const docTemplate = `{
                 "platform": {
                     "type": "string"
                 },
+                "secret": {
+                    "type": "string"
+                },
                 "target_id": {
                     "type": "string"
                 }
             }
         },
+        "entity.DeadLetter": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "failed_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "payload": {
+                    "type": "string"
+                },
+                "received_at": {
+                    "type": "string"
+                },
+                "redriven_at": {
+                    "type": "string"
+                },
+                "topic": {
+                    "type": "string"
+                },
+                "uuid": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.Delivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "channel_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "uuid": {
+                    "type": "string"
+                }
+            }
+        },
         "entity.Token": {
             "type": "object",
             "required": [
@@ -642,6 +1151,96 @@ const docTemplate = `{
                 }
             }
         },
+        "value.NotificationAccepted": {
+            "type": "object",
+            "properties": {
+                "accepted_at": {
+                    "type": "string"
+                },
+                "channels": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "send_at": {
+                    "type": "string"
+                },
+                "status_url": {
+                    "type": "string"
+                },
+                "uuid": {
+                    "type": "string"
+                }
+            }
+        },
+        "value.NotificationBatchInput": {
+            "type": "object",
+            "required": [
+                "notifications"
+            ],
+            "properties": {
+                "notifications": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/value.NotificationInput"
+                    }
+                }
+            }
+        },
+        "value.NotificationBatchOutput": {
+            "type": "object",
+            "properties": {
+                "accepted": {
+                    "type": "integer"
+                },
+                "duplicates": {
+                    "type": "integer"
+                },
+                "invalid": {
+                    "type": "integer"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/value.NotificationBatchResult"
+                    }
+                }
+            }
+        },
+        "value.NotificationBatchResult": {
+            "type": "object",
+            "properties": {
+                "accepted_at": {
+                    "type": "string"
+                },
+                "channels": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "error": {
+                    "type": "string"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "send_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "status_url": {
+                    "type": "string"
+                },
+                "uuid": {
+                    "type": "string"
+                }
+            }
+        },
         "value.NotificationInput": {
             "type": "object",
             "required": [
@@ -662,9 +1261,15 @@ const docTemplate = `{
                 "event": {
                     "$ref": "#/definitions/value.Event"
                 },
+                "expires_at": {
+                    "type": "string"
+                },
                 "message": {
                     "type": "string"
                 },
+                "send_at": {
+                    "type": "string"
+                },
                 "title": {
                     "type": "string"
                 },
@@ -672,6 +1277,43 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "value.NotificationStatusOutput": {
+            "type": "object",
+            "properties": {
+                "accepted_at": {
+                    "type": "string"
+                },
+                "channels": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.Delivery"
+                    }
+                },
+                "retries": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "uuid": {
+                    "type": "string"
+                }
+            }
+        },
+        "value.RescheduleInput": {
+            "type": "object",
+            "required": [
+                "send_at"
+            ],
+            "properties": {
+                "send_at": {
+                    "type": "string"
+                }
+            }
         }
     }
 }`
@@ -50,7 +50,7 @@ func (cc *ChannelController) CreateChannel(httpContext *gin.Context) {
 
 	usecase := usecase.NewCreateChannelUsecase(
 		infra.App.Repositories.ChannelRepository,
-		infra.App.Email,
+		infra.App.Providers,
 		cc.logger,
 	)
 
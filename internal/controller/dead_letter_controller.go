@@ -0,0 +1,141 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gurodrigues-dev/notifier-app/internal/infra"
+	"github.com/gurodrigues-dev/notifier-app/internal/infra/contracts"
+	"github.com/gurodrigues-dev/notifier-app/internal/usecase"
+	"github.com/jinzhu/gorm"
+)
+
+const (
+	defaultDeadLetterLimit = 50
+	maxDeadLetterLimit     = 100
+)
+
+type DeadLetterController struct {
+	logger contracts.Logger
+}
+
+func NewDeadLetterController(
+	logger contracts.Logger,
+) *DeadLetterController {
+	return &DeadLetterController{
+		logger: logger,
+	}
+}
+
+// ListDeadLetters godoc
+// @Summary List dead letters
+// @Description Lists the notifications sent to the dead-letter topic, newest first.
+// @Tags dead-letter
+// @Produce json
+// @Param limit query int false "Page size (default 50, max 100)"
+// @Param offset query int false "Page offset"
+// @Success 200 {array} entity.DeadLetter "Dead letters retrieved successfully"
+// @Failure 400 {object} map[string]string "Invalid pagination"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /dead-letter [get]
+func (dc *DeadLetterController) ListDeadLetters(httpContext *gin.Context) {
+	limit, err := strconv.Atoi(httpContext.DefaultQuery("limit", strconv.Itoa(defaultDeadLetterLimit)))
+	if err != nil || limit <= 0 || limit > maxDeadLetterLimit {
+		httpContext.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+		return
+	}
+
+	offset, err := strconv.Atoi(httpContext.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		httpContext.JSON(http.StatusBadRequest, gin.H{"error": "invalid offset"})
+		return
+	}
+
+	usecase := usecase.NewListDeadLettersUsecase(
+		infra.App.Repositories.DeadLetterRepository,
+		dc.logger,
+	)
+
+	deadLetters, err := usecase.ListDeadLetters(limit, offset)
+	if err != nil {
+		httpContext.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	httpContext.JSON(http.StatusOK, deadLetters)
+}
+
+// GetDeadLetter godoc
+// @Summary Get dead letter by ID
+// @Description Retrieves a dead letter with its original payload, error chain and attempts.
+// @Tags dead-letter
+// @Produce json
+// @Param id path string true "Dead letter ID"
+// @Success 200 {object} entity.DeadLetter "Dead letter retrieved successfully"
+// @Failure 404 {object} map[string]string "Dead letter not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /dead-letter/{id} [get]
+func (dc *DeadLetterController) GetDeadLetter(httpContext *gin.Context) {
+	id := httpContext.Param("id")
+
+	usecase := usecase.NewGetDeadLetterUsecase(
+		infra.App.Repositories.DeadLetterRepository,
+		dc.logger,
+	)
+
+	deadLetter, err := usecase.GetDeadLetter(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			httpContext.JSON(http.StatusNotFound, gin.H{"error": "dead letter not found"})
+			return
+		}
+		httpContext.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	httpContext.JSON(http.StatusOK, deadLetter)
+}
+
+// RedriveDeadLetter godoc
+// @Summary Re-drive dead letter
+// @Description Sends a dead letter back to the main topic with its retries reset.
+// @Tags dead-letter
+// @Produce json
+// @Param id path string true "Dead letter ID"
+// @Success 200 {object} map[string]string "Dead letter re-driven successfully"
+// @Failure 404 {object} map[string]string "Dead letter not found"
+// @Failure 409 {object} map[string]string "Dead letter was already re-driven"
+// @Failure 422 {object} map[string]string "Dead letter payload is not a notification"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /dead-letter/{id}/redrive [post]
+func (dc *DeadLetterController) RedriveDeadLetter(httpContext *gin.Context) {
+	id := httpContext.Param("id")
+
+	redrive := usecase.NewRedriveDeadLetterUsecase(
+		infra.App.Repositories.DeadLetterRepository,
+		infra.App.Queue,
+		dc.logger,
+	)
+
+	err := redrive.RedriveDeadLetter(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			httpContext.JSON(http.StatusNotFound, gin.H{"error": "dead letter not found"})
+			return
+		}
+		if errors.Is(err, usecase.ErrDeadLetterAlreadyRedriven) {
+			httpContext.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, usecase.ErrDeadLetterNotRedrivable) {
+			httpContext.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+		httpContext.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	httpContext.JSON(http.StatusOK, gin.H{"message": "dead letter redriven successfully"})
+}
@@ -1,6 +1,8 @@
 package controller
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -31,8 +33,10 @@ func NewNotificationController(
 // @Accept json
 // @Produce json
 // @Param notification body value.NotificationInput true "Notification request body"
-// @Success 200 {string} string "Notification sent successfully"
+// @Success 202 {object} value.NotificationAccepted "Notification accepted"
+// @Header 202 {string} Location "Notification status URL"
 // @Failure 400 {object} map[string]string "Invalid request body"
+// @Failure 409 {object} map[string]string "Notification already exists"
 // @Failure 422 {object} map[string]string "Unprocessable entity"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /notification [post]
@@ -49,21 +53,81 @@ func (nc *NotificationController) CreateNotification(httpContext *gin.Context) {
 		return
 	}
 
-	usecase := usecase.NewCreateNotificationUsecase(
+	create := usecase.NewCreateNotificationUsecase(
 		infra.App.Repositories.NotificationRepository,
 		infra.App.Repositories.ChannelRepository,
 		infra.App.Cache,
-		infra.App.Queue,
 		nc.logger,
 	)
 
-	err := usecase.CreateNotification(requestParams)
+	accepted, err := create.CreateNotification(requestParams)
+	if errors.Is(err, usecase.ErrInvalidExpiry) {
+		httpContext.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, usecase.ErrNotificationAlreadyExists) {
+		httpContext.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		httpContext.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	accepted.StatusURL = fmt.Sprintf("/api/v1/notification/%s/status", accepted.UUID)
+	httpContext.Header("Location", accepted.StatusURL)
+	httpContext.JSON(http.StatusAccepted, accepted)
+}
+
+// CreateNotificationBatch godoc
+// @Summary Create notifications in batch
+// @Description Creates many notifications at once, returning whether each one was accepted, a duplicate or invalid.
+// @Tags notification
+// @Accept json
+// @Produce json
+// @Param notifications body value.NotificationBatchInput true "Notification batch request body"
+// @Success 200 {object} value.NotificationBatchOutput "Result of each notification"
+// @Failure 400 {object} map[string]string "Invalid request body"
+// @Failure 422 {object} map[string]string "Unprocessable entity"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /notifications/batch [post]
+func (nc *NotificationController) CreateNotificationBatch(httpContext *gin.Context) {
+	var requestParams value.NotificationBatchInput
+	if err := httpContext.BindJSON(&requestParams); err != nil {
+		httpContext.JSON(http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(requestParams); err != nil {
+		httpContext.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	create := usecase.NewCreateNotificationBatchUsecase(
+		infra.App.Repositories.NotificationRepository,
+		infra.App.Repositories.ChannelRepository,
+		infra.App.Cache,
+		nc.logger,
+	)
+
+	output, err := create.CreateNotificationBatch(requestParams.Notifications)
+	if errors.Is(err, usecase.ErrBatchTooLarge) {
+		httpContext.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		httpContext.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 		return
 	}
 
-	httpContext.JSON(http.StatusOK, "notification sent successfully")
+	for i, result := range output.Results {
+		if result.Status == value.BatchAccepted {
+			output.Results[i].StatusURL = fmt.Sprintf("/api/v1/notification/%s/status", result.UUID)
+		}
+	}
+
+	httpContext.JSON(http.StatusOK, output)
 }
 
 // GetNotification godoc
@@ -97,3 +161,120 @@ func (nc *NotificationController) GetNotification(httpContext *gin.Context) {
 	httpContext.JSON(http.StatusOK, notifyError)
 	return
 }
+
+// GetNotificationStatus godoc
+// @Summary Get notification status by UUID
+// @Description Retrieves the lifecycle of a notification and the delivery status of each of its channels.
+// @Tags notification
+// @Produce json
+// @Param id path string true "Notification UUID"
+// @Success 200 {object} value.NotificationStatusOutput "Notification status retrieved successfully"
+// @Failure 404 {object} map[string]string "Notification not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /notification/{id}/status [get]
+func (nc *NotificationController) GetNotificationStatus(httpContext *gin.Context) {
+	uuid := httpContext.Param("id")
+
+	usecase := usecase.NewGetNotificationStatusUsecase(
+		infra.App.Repositories.NotificationRepository,
+		infra.App.Repositories.DeliveryRepository,
+		nc.logger,
+	)
+
+	status, err := usecase.GetNotificationStatus(uuid)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			httpContext.JSON(http.StatusNotFound, gin.H{"error": "notification not found"})
+			return
+		}
+		httpContext.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	httpContext.JSON(http.StatusOK, status)
+}
+
+// CancelScheduledNotification godoc
+// @Summary Cancel a scheduled notification
+// @Description Cancels a notification scheduled with send_at before it is sent.
+// @Tags notification
+// @Produce json
+// @Param id path string true "Notification UUID"
+// @Success 200 {object} map[string]string "Notification cancelled"
+// @Failure 404 {object} map[string]string "Notification not found"
+// @Failure 409 {object} map[string]string "Notification is not scheduled"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /notification/{id}/schedule [delete]
+func (nc *NotificationController) CancelScheduledNotification(httpContext *gin.Context) {
+	uuid := httpContext.Param("id")
+
+	usecase := usecase.NewCancelScheduledNotificationUsecase(
+		infra.App.Repositories.NotificationRepository,
+		nc.logger,
+	)
+
+	err := usecase.CancelScheduledNotification(uuid)
+	if err != nil {
+		nc.scheduleError(httpContext, err)
+		return
+	}
+
+	httpContext.JSON(http.StatusOK, gin.H{"message": "notification cancelled successfully"})
+}
+
+// RescheduleNotification godoc
+// @Summary Reschedule a scheduled notification
+// @Description Moves a notification scheduled with send_at to a new time before it is sent. The new time must be before the notification expires_at.
+// @Tags notification
+// @Accept json
+// @Produce json
+// @Param id path string true "Notification UUID"
+// @Param schedule body value.RescheduleInput true "New send time"
+// @Success 200 {object} map[string]string "Notification rescheduled"
+// @Failure 400 {object} map[string]string "Invalid request body, send_at in the past or after expires_at"
+// @Failure 404 {object} map[string]string "Notification not found"
+// @Failure 409 {object} map[string]string "Notification is not scheduled"
+// @Failure 422 {object} map[string]string "Unprocessable entity"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /notification/{id}/schedule [patch]
+func (nc *NotificationController) RescheduleNotification(httpContext *gin.Context) {
+	uuid := httpContext.Param("id")
+
+	var requestParams value.RescheduleInput
+	if err := httpContext.BindJSON(&requestParams); err != nil {
+		httpContext.JSON(http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(requestParams); err != nil {
+		httpContext.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	usecase := usecase.NewRescheduleNotificationUsecase(
+		infra.App.Repositories.NotificationRepository,
+		nc.logger,
+	)
+
+	err := usecase.RescheduleNotification(uuid, requestParams.SendAt)
+	if err != nil {
+		nc.scheduleError(httpContext, err)
+		return
+	}
+
+	httpContext.JSON(http.StatusOK, gin.H{"message": "notification rescheduled successfully"})
+}
+
+func (nc *NotificationController) scheduleError(httpContext *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		httpContext.JSON(http.StatusNotFound, gin.H{"error": "notification not found"})
+	case errors.Is(err, usecase.ErrSendAtInPast), errors.Is(err, usecase.ErrInvalidExpiry):
+		httpContext.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrNotificationNotScheduled):
+		httpContext.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		httpContext.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -10,4 +10,5 @@ type ChannelRepository interface {
 	GetByGroups(groups []string) ([]entity.Channel, error)
 	GetByPlatform(platform string) ([]entity.Channel, error)
 	DeleteByID(id string) error
+	GetSecretByID(id int) (string, error)
 }
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/gurodrigues-dev/notifier-app/internal/entity"
+)
+
+type DeadLetterRepository interface {
+	CreateDeadLetter(deadLetter *entity.DeadLetter) error
+	GetPendingDeadLetter(uuid string, attempts int64) (*entity.DeadLetter, error)
+	ListDeadLetters(limit, offset int) ([]entity.DeadLetter, error)
+	GetDeadLetterByID(id string) (*entity.DeadLetter, error)
+	MarkRedriven(id int, redrivenAt time.Time) error
+	UnmarkRedriven(id int) error
+}
@@ -0,0 +1,9 @@
+package repository
+
+import "github.com/gurodrigues-dev/notifier-app/internal/entity"
+
+type DeliveryRepository interface {
+	StartDelivery(uuid string, channelID int) error
+	FinishDelivery(uuid string, channelID int, status, lastError, provider string) error
+	GetDeliveriesByUUID(uuid string) ([]entity.Delivery, error)
+}
@@ -0,0 +1,13 @@
+package repository
+
+import "errors"
+
+// ErrDuplicateKey is returned when a write violates a unique constraint.
+var ErrDuplicateKey = errors.New("duplicate key")
+
+// ErrNotScheduled is returned when a scheduled notification was already
+// published, or is being published, and can no longer be changed.
+var ErrNotScheduled = errors.New("notification is not scheduled")
+
+// ErrAlreadyRedriven is returned when a dead letter was already re-driven.
+var ErrAlreadyRedriven = errors.New("dead letter already redriven")
@@ -1,10 +1,19 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/gurodrigues-dev/notifier-app/internal/entity"
 )
 
 type NotificationRepository interface {
 	CreateNotification(notification *entity.NotificationError) error
 	GetNotificationByID(id string) (*entity.NotificationError, error)
+	CreateRecordWithOutbox(record *entity.NotificationRecord, message *entity.OutboxMessage) error
+	CreateRecordsWithOutbox(records []*entity.NotificationRecord, messages []*entity.OutboxMessage) ([]string, error)
+	UpdateRecordStatus(uuid, status string, retries int64) error
+	AdvanceRecordStatus(uuid string, from []string, to string) error
+	CancelScheduled(uuid string) error
+	RescheduleRecord(uuid string, sendAt time.Time) error
+	GetRecordByUUID(uuid string) (*entity.NotificationRecord, error)
 }
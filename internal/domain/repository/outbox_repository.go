@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/gurodrigues-dev/notifier-app/internal/entity"
+)
+
+type OutboxRepository interface {
+	ClaimPending(limit int, lease time.Duration) ([]entity.OutboxMessage, error)
+	MarkSent(id int, at time.Time) error
+	MarkFailed(id int, lastError string) error
+}
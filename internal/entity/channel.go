@@ -1,8 +1,12 @@
 package entity
 
+// Channel is where notifications are sent. Secret holds the credential of
+// platforms that need one besides the target; it is only read by their
+// provider and never returned once stored.
 type Channel struct {
 	ID       int    `json:"id"`
 	Platform string `json:"platform" validate:"required"`
 	TargetID string `json:"target_id" validate:"required"`
 	Group    string `json:"group" validate:"required"`
+	Secret   string `json:"secret,omitempty"`
 }
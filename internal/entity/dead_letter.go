@@ -0,0 +1,21 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// DeadLetter is a message that will not be delivered, with the error of every
+// attempt. Topic is the main topic it is re-driven to.
+type DeadLetter struct {
+	ID         int            `json:"id"`
+	UUID       string         `json:"uuid"`
+	Topic      string         `json:"topic"`
+	Payload    string         `json:"payload"`
+	Errors     pq.StringArray `json:"errors" gorm:"type:text[]" swaggertype:"array,string"`
+	Attempts   int64          `json:"attempts"`
+	ReceivedAt time.Time      `json:"received_at"`
+	FailedAt   time.Time      `json:"failed_at"`
+	RedrivenAt *time.Time     `json:"redriven_at"`
+}
@@ -0,0 +1,16 @@
+package entity
+
+import "time"
+
+// Delivery is the state of a notification on a single channel.
+type Delivery struct {
+	ID        int       `json:"id"`
+	UUID      string    `json:"uuid"`
+	ChannelID int       `json:"channel_id"`
+	Status    string    `json:"status"`
+	Attempts  int64     `json:"attempts"`
+	LastError string    `json:"last_error"`
+	Provider  string    `json:"provider,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
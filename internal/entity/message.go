@@ -1,13 +1,18 @@
 package entity
 
+import "time"
+
 type Notification struct {
-	ID       int             `json:"id"`
-	UUID     string          `json:"uuid"`
-	Title    string          `json:"title"`
-	Message  string          `json:"message"`
-	Channels map[int]Channel `json:"channels"`
-	Event    Event           `json:"event"`
-	Retries  int64           `json:"retries"`
+	ID        int             `json:"id"`
+	UUID      string          `json:"uuid"`
+	Title     string          `json:"title"`
+	Message   string          `json:"message"`
+	Channels  map[int]Channel `json:"channels"`
+	Event     Event           `json:"event"`
+	Retries   int64           `json:"-"`
+	NotBefore int64           `json:"-"`
+	ExpiresAt *time.Time      `json:"expires_at,omitempty"`
+	Errors    []string        `json:"errors,omitempty"`
 }
 
 type Event struct {
@@ -20,6 +25,22 @@ type Event struct {
 	CostCents int64  `json:"cost_cents"`
 }
 
+// NotificationRecord is the lifecycle of a notification, from its acceptance
+// by the API until it is delivered or given up by the dispatcher.
+type NotificationRecord struct {
+	ID        int       `json:"id"`
+	UUID      string    `json:"uuid"`
+	Body      []byte    `json:"-"`
+	Status    string    `json:"status"`
+	Retries   int64     `json:"retries"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (NotificationRecord) TableName() string {
+	return "notifications"
+}
+
 type NotificationError struct {
 	ID    int    `json:"id"`
 	UUID  string `json:"uuid"`
@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+// OutboxMessage is a queue message stored in the same transaction as the
+// data it belongs to, waiting to be published by the outbox relay once
+// AvailableAt is reached.
+type OutboxMessage struct {
+	ID          int        `json:"id"`
+	Topic       string     `json:"topic"`
+	Key         string     `json:"key"`
+	Payload     string     `json:"payload"`
+	Headers     string     `json:"headers" gorm:"type:jsonb"`
+	Attempts    int64      `json:"attempts"`
+	LastError   string     `json:"last_error"`
+	LockedUntil *time.Time `json:"locked_until"`
+	AvailableAt time.Time  `json:"available_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	SentAt      *time.Time `json:"sent_at"`
+}
+
+func (OutboxMessage) TableName() string {
+	return "outbox"
+}
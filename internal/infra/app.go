@@ -3,6 +3,7 @@ package infra
 import (
 	"github.com/gurodrigues-dev/notifier-app/internal/infra/contracts"
 	"github.com/gurodrigues-dev/notifier-app/internal/infra/persistence"
+	"github.com/gurodrigues-dev/notifier-app/internal/infra/provider"
 )
 
 type Application struct {
@@ -13,6 +14,8 @@ type Application struct {
 	Logger       contracts.Logger
 	Queue        contracts.Queue
 	Metrics      contracts.Metrics
+	Webhook      contracts.Webhook
+	Providers    *provider.Registry
 }
 
 var App Application
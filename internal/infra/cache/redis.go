@@ -41,6 +41,20 @@ func (c CacheImpl) Set(key string, value any, expiration time.Duration) error {
 	return nil
 }
 
+// SetNX sets key only if it does not exist yet and reports whether it did.
+func (c CacheImpl) SetNX(key string, value any, expiration time.Duration) (bool, error) {
+	serializedValue, err := json.Marshal(value)
+	if err != nil {
+		return false, err
+	}
+
+	return c.client.SetNX(context.Background(), key, serializedValue, expiration).Result()
+}
+
+func (c CacheImpl) Delete(key string) error {
+	return c.client.Del(context.Background(), key).Err()
+}
+
 func (c CacheImpl) Get(key string) (string, error) {
 	statusCmd := c.client.Get(context.Background(), key)
 
@@ -66,3 +80,7 @@ func (c CacheImpl) Expire(key string, expiration time.Duration) (bool, error) {
 
 	return result, nil
 }
+
+func (c CacheImpl) Close() error {
+	return c.client.Close()
+}
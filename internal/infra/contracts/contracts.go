@@ -1,6 +1,7 @@
 package contracts
 
 import (
+	"context"
 	"io"
 	"time"
 
@@ -11,10 +12,17 @@ import (
 )
 
 type SESIface interface {
-	SendEmail(email *entity.Email) error
+	SendEmail(ctx context.Context, email *entity.Email) error
 	VerifyEmail(email string) error
 }
 
+// EmailRouter is implemented by email senders that report the name of the
+// provider, such as ses or smtp, that delivered the email.
+type EmailRouter interface {
+	SESIface
+	SendEmailVia(ctx context.Context, email *entity.Email) (string, error)
+}
+
 type PostgresIface interface {
 	Client() *gorm.DB
 	Close() error
@@ -23,7 +31,10 @@ type PostgresIface interface {
 type Cacher interface {
 	Set(key string, value any, expiration time.Duration) error
 	Get(key string) (string, error)
+	SetNX(key string, value any, expiration time.Duration) (bool, error)
+	Delete(key string) error
 	Expire(key string, expiration time.Duration) (bool, error)
+	Close() error
 }
 
 type Logger interface {
@@ -32,8 +43,10 @@ type Logger interface {
 }
 
 type Queue interface {
-	Produce(topic, message string) error
-	Consumer(topic, group string, handler func(message string)) error
+	Produce(topic string, message Message) error
+	ProduceBatch(topic string, messages []Message) error
+	Consumer(ctx context.Context, topic, group string, handler func(ctx context.Context, message Message) error) error
+	Close() error
 }
 
 type Metrics interface {
@@ -44,7 +57,59 @@ type Metrics interface {
 }
 
 type Webhook interface {
-	Post(url, contentType string, body io.Reader) (*HTTPResponse, error)
+	Post(ctx context.Context, url, contentType string, body io.Reader) (*HTTPResponse, error)
+	PostWithHeaders(ctx context.Context, url string, headers map[string]string, body io.Reader) (*HTTPResponse, error)
+}
+
+type Provider interface {
+	Platform() string
+	ValidateTarget(channel *entity.Channel) error
+	Render(channel entity.Channel, notification *entity.Notification) ([]byte, error)
+	Send(ctx context.Context, channel entity.Channel, notification *entity.Notification) error
+}
+
+// SecretGenerator is implemented by providers whose channels get a secret
+// generated on creation instead of one given by the client.
+type SecretGenerator interface {
+	GenerateSecret() (string, error)
+}
+
+// SecretValidator is implemented by providers whose channels take a secret
+// given by the client, such as a bot token. Providers implementing neither
+// this nor SecretGenerator have no use for a secret.
+type SecretValidator interface {
+	ValidateSecret(secret string) error
+}
+
+// Router is implemented by providers that deliver through one of several
+// upstream services and report which one delivered the notification.
+type Router interface {
+	SendVia(ctx context.Context, channel entity.Channel, notification *entity.Notification) (string, error)
+}
+
+type stoppingKey struct{}
+
+// WithStopping returns a copy of ctx carrying stopping, a channel closed once
+// the consumer handling the message stops fetching. The handler context
+// itself is only cancelled when in-flight work must be cut off.
+func WithStopping(ctx context.Context, stopping <-chan struct{}) context.Context {
+	return context.WithValue(ctx, stoppingKey{}, stopping)
+}
+
+// Stopping returns the channel closed once the consumer handling the message
+// of ctx stops fetching, so long waits can give up early. It is nil, and so
+// never ready, when ctx does not carry one.
+func Stopping(ctx context.Context) <-chan struct{} {
+	stopping, _ := ctx.Value(stoppingKey{}).(<-chan struct{})
+	return stopping
+}
+
+// Message is a record written to or read from the queue. Messages with the
+// same key keep their order.
+type Message struct {
+	Key     string
+	Value   string
+	Headers map[string]string
 }
 
 type HTTPResponse struct {
@@ -0,0 +1,188 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/gurodrigues-dev/notifier-app/internal/entity"
+	"github.com/gurodrigues-dev/notifier-app/internal/infra/contracts"
+	"github.com/gurodrigues-dev/notifier-app/internal/value"
+)
+
+var (
+	ErrNoEmailProvider = errors.New("no email provider in rotation")
+
+	// ErrRejected marks an email refused because of the email itself, such
+	// as an invalid or unknown recipient, rather than because the provider
+	// failed. It is not held against the provider.
+	ErrRejected = errors.New("email rejected")
+)
+
+// Sender is an email provider with the name it is configured by.
+type Sender struct {
+	Name     string
+	Provider contracts.SESIface
+}
+
+// FailoverImpl sends each email through the first provider that accepts it,
+// in the configured order. A provider that fails Threshold times in a row is
+// taken out of rotation for Cooldown; after that a single email is let
+// through to probe it, and it is back in rotation once one succeeds.
+type FailoverImpl struct {
+	senders []*breakerSender
+	breaker value.EmailBreaker
+	logger  contracts.Logger
+	now     func() time.Time
+}
+
+type breakerSender struct {
+	Sender
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+}
+
+func NewFailoverImpl(breaker value.EmailBreaker, logger contracts.Logger, senders ...Sender) *FailoverImpl {
+	failover := &FailoverImpl{
+		breaker: breaker,
+		logger:  logger,
+		now:     time.Now,
+	}
+	for _, sender := range senders {
+		failover.senders = append(failover.senders, &breakerSender{Sender: sender})
+	}
+	return failover
+}
+
+func (fi *FailoverImpl) SendEmail(ctx context.Context, email *entity.Email) error {
+	_, err := fi.SendEmailVia(ctx, email)
+	return err
+}
+
+// SendEmailVia sends the email through the first provider in rotation that
+// accepts it, giving each its share of the time left, and returns the name of
+// that provider. A provider that runs out of time failed like any other, but
+// errors caused by the caller cancelling ctx or by the email itself are not
+// held against it.
+func (fi *FailoverImpl) SendEmailVia(ctx context.Context, email *entity.Email) (string, error) {
+	var errs []error
+	for i, sender := range fi.senders {
+		if ctx.Err() != nil {
+			errs = append(errs, ctx.Err())
+			break
+		}
+
+		if !sender.allow(fi.now(), fi.breaker.Cooldown) {
+			continue
+		}
+
+		attemptCtx, cancel := attemptContext(ctx, len(fi.senders)-i)
+		err := sender.Provider.SendEmail(attemptCtx, email)
+		cancel()
+		if err == nil {
+			fi.succeeded(sender)
+			fi.logger.Infof(fmt.Sprintf("email delivered by provider %s", sender.Name))
+			return sender.Name, nil
+		}
+
+		errs = append(errs, fmt.Errorf("%s: %w", sender.Name, err))
+		if errors.Is(ctx.Err(), context.Canceled) {
+			break
+		}
+
+		fi.logger.Errorf(fmt.Sprintf("error sending email with provider %s: %v", sender.Name, err))
+		if !errors.Is(err, ErrRejected) {
+			fi.failed(sender)
+		}
+	}
+
+	if len(errs) == 0 {
+		return "", ErrNoEmailProvider
+	}
+	return "", errors.Join(errs...)
+}
+
+// VerifyEmail asks the providers in order until one accepts or rejects the
+// address. It bypasses the breaker: an address a client got wrong says
+// nothing about the health of a provider.
+func (fi *FailoverImpl) VerifyEmail(email string) error {
+	var errs []error
+	for _, sender := range fi.senders {
+		err := sender.Provider.VerifyEmail(email)
+		if err == nil || errors.Is(err, ErrRejected) {
+			return err
+		}
+
+		fi.logger.Errorf(fmt.Sprintf("error verifying email with provider %s: %v", sender.Name, err))
+		errs = append(errs, fmt.Errorf("%s: %w", sender.Name, err))
+	}
+	return errors.Join(errs...)
+}
+
+// Close closes the providers that hold connections.
+func (fi *FailoverImpl) Close() error {
+	var errs []error
+	for _, sender := range fi.senders {
+		if closer, ok := sender.Provider.(io.Closer); ok {
+			errs = append(errs, closer.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// attemptContext bounds the attempt of one provider to an even share of the
+// time left before the deadline of ctx among the remaining providers, so one
+// that hangs still leaves time for the next. The last provider gets all of it.
+func attemptContext(ctx context.Context, remaining int) (context.Context, context.CancelFunc) {
+	deadline, ok := ctx.Deadline()
+	if !ok || remaining <= 1 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, time.Until(deadline)/time.Duration(remaining))
+}
+
+func (fi *FailoverImpl) succeeded(sender *breakerSender) {
+	sender.mu.Lock()
+	defer sender.mu.Unlock()
+
+	if !sender.openUntil.IsZero() {
+		fi.logger.Infof(fmt.Sprintf("email provider %s is back in rotation", sender.Name))
+	}
+	sender.failures = 0
+	sender.openUntil = time.Time{}
+}
+
+func (fi *FailoverImpl) failed(sender *breakerSender) {
+	sender.mu.Lock()
+	defer sender.mu.Unlock()
+
+	sender.failures++
+	if sender.failures >= fi.breaker.Threshold {
+		sender.openUntil = fi.now().Add(fi.breaker.Cooldown)
+		fi.logger.Errorf(fmt.Sprintf("email provider %s out of rotation for %s after %d failures", sender.Name, fi.breaker.Cooldown, sender.failures))
+	}
+}
+
+// allow reports whether the provider may be tried. Once its cooldown is over
+// the next caller gets to probe it, and the cooldown is pushed back so
+// concurrent callers keep skipping it until the probe succeeds.
+func (bs *breakerSender) allow(now time.Time, cooldown time.Duration) bool {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	if bs.openUntil.IsZero() {
+		return true
+	}
+
+	if now.Before(bs.openUntil) {
+		return false
+	}
+
+	bs.openUntil = now.Add(cooldown)
+	return true
+}
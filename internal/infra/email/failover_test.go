@@ -0,0 +1,242 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gurodrigues-dev/notifier-app/internal/entity"
+	"github.com/gurodrigues-dev/notifier-app/internal/value"
+	"github.com/gurodrigues-dev/notifier-app/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestFailoverImpl_SendEmail(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(ses, smtp *mocks.SESIface)
+		wantErr bool
+	}{
+		{
+			name: "there is to return success with the first provider",
+			setup: func(ses, smtp *mocks.SESIface) {
+				ses.On("SendEmail", mock.Anything, mock.Anything).Return(nil).Once()
+			},
+			wantErr: false,
+		},
+		{
+			name: "there is to return success with the second provider",
+			setup: func(ses, smtp *mocks.SESIface) {
+				ses.On("SendEmail", mock.Anything, mock.Anything).Return(errors.New("throttling")).Once()
+				smtp.On("SendEmail", mock.Anything, mock.Anything).Return(nil).Once()
+			},
+			wantErr: false,
+		},
+		{
+			name: "there is to return error when every provider fails",
+			setup: func(ses, smtp *mocks.SESIface) {
+				ses.On("SendEmail", mock.Anything, mock.Anything).Return(errors.New("throttling")).Once()
+				smtp.On("SendEmail", mock.Anything, mock.Anything).Return(errors.New("connection refused")).Once()
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ses := mocks.NewSESIface(t)
+			smtp := mocks.NewSESIface(t)
+			logger := mocks.NewLogger(t)
+			logger.On("Infof", mock.Anything, mock.Anything).Return().Maybe()
+			logger.On("Errorf", mock.Anything, mock.Anything).Return().Maybe()
+			tt.setup(ses, smtp)
+
+			failover := NewFailoverImpl(value.EmailBreaker{Threshold: 5, Cooldown: time.Minute}, logger,
+				Sender{Name: value.SESEmailProvider, Provider: ses},
+				Sender{Name: value.SMTPEmailProvider, Provider: smtp},
+			)
+
+			err := failover.SendEmail(context.Background(), &entity.Email{Recipient: "user@example.com"})
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
+
+func TestFailoverImpl_SendEmailBreaker(t *testing.T) {
+	ses := mocks.NewSESIface(t)
+	smtp := mocks.NewSESIface(t)
+	logger := mocks.NewLogger(t)
+	logger.On("Infof", mock.Anything, mock.Anything).Return()
+	logger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+	now := time.Now()
+	failover := NewFailoverImpl(value.EmailBreaker{Threshold: 2, Cooldown: time.Minute}, logger,
+		Sender{Name: value.SESEmailProvider, Provider: ses},
+		Sender{Name: value.SMTPEmailProvider, Provider: smtp},
+	)
+	failover.now = func() time.Time { return now }
+
+	email := &entity.Email{Recipient: "user@example.com"}
+	smtp.On("SendEmail", mock.Anything, email).Return(nil)
+
+	// Two failures take SES out of rotation, so the third email skips it.
+	ses.On("SendEmail", mock.Anything, email).Return(errors.New("throttling")).Twice()
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, failover.SendEmail(context.Background(), email))
+	}
+	ses.AssertNumberOfCalls(t, "SendEmail", 2)
+	smtp.AssertNumberOfCalls(t, "SendEmail", 3)
+
+	via, err := failover.SendEmailVia(context.Background(), email)
+	assert.NoError(t, err)
+	assert.Equal(t, value.SMTPEmailProvider, via)
+	smtp.AssertNumberOfCalls(t, "SendEmail", 4)
+
+	// After the cooldown a probe goes to SES and puts it back in rotation.
+	now = now.Add(time.Minute)
+	ses.On("SendEmail", mock.Anything, email).Return(nil).Twice()
+	for i := 0; i < 2; i++ {
+		assert.NoError(t, failover.SendEmail(context.Background(), email))
+	}
+	ses.AssertNumberOfCalls(t, "SendEmail", 4)
+	smtp.AssertNumberOfCalls(t, "SendEmail", 4)
+}
+
+func TestFailoverImpl_SendEmailNoProvider(t *testing.T) {
+	ses := mocks.NewSESIface(t)
+	logger := mocks.NewLogger(t)
+	logger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+	failover := NewFailoverImpl(value.EmailBreaker{Threshold: 1, Cooldown: time.Minute}, logger,
+		Sender{Name: value.SESEmailProvider, Provider: ses},
+	)
+
+	ses.On("SendEmail", mock.Anything, mock.Anything).Return(errors.New("throttling")).Once()
+	assert.Error(t, failover.SendEmail(context.Background(), &entity.Email{}))
+
+	err := failover.SendEmail(context.Background(), &entity.Email{})
+	assert.ErrorIs(t, err, ErrNoEmailProvider)
+}
+
+func TestFailoverImpl_SendEmailHangingProvider(t *testing.T) {
+	ses := mocks.NewSESIface(t)
+	smtp := mocks.NewSESIface(t)
+	logger := mocks.NewLogger(t)
+	logger.On("Infof", mock.Anything, mock.Anything).Return()
+	logger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+	failover := NewFailoverImpl(value.EmailBreaker{Threshold: 2, Cooldown: time.Minute}, logger,
+		Sender{Name: value.SESEmailProvider, Provider: ses},
+		Sender{Name: value.SMTPEmailProvider, Provider: smtp},
+	)
+
+	// SES hangs until its share of the deadline is over.
+	ses.On("SendEmail", mock.Anything, mock.Anything).Return(func(ctx context.Context, email *entity.Email) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	smtp.On("SendEmail", mock.Anything, mock.Anything).Return(nil)
+
+	for i := 0; i < 3; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		err := failover.SendEmail(ctx, &entity.Email{Recipient: "user@example.com"})
+		cancel()
+		assert.NoError(t, err)
+	}
+
+	// The timeouts counted against SES, so the third email skipped it.
+	ses.AssertNumberOfCalls(t, "SendEmail", 2)
+	smtp.AssertNumberOfCalls(t, "SendEmail", 3)
+}
+
+func TestFailoverImpl_SendEmailRejected(t *testing.T) {
+	ses := mocks.NewSESIface(t)
+	smtp := mocks.NewSESIface(t)
+	logger := mocks.NewLogger(t)
+	logger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+	failover := NewFailoverImpl(value.EmailBreaker{Threshold: 1, Cooldown: time.Minute}, logger,
+		Sender{Name: value.SESEmailProvider, Provider: ses},
+		Sender{Name: value.SMTPEmailProvider, Provider: smtp},
+	)
+
+	rejected := fmt.Errorf("%w: MessageRejected", ErrRejected)
+	ses.On("SendEmail", mock.Anything, mock.Anything).Return(rejected)
+	smtp.On("SendEmail", mock.Anything, mock.Anything).Return(fmt.Errorf("%w: 550 mailbox unavailable", ErrRejected))
+
+	for i := 0; i < 3; i++ {
+		err := failover.SendEmail(context.Background(), &entity.Email{Recipient: "unknown@example.com"})
+		assert.ErrorIs(t, err, ErrRejected)
+	}
+
+	// Rejections are not held against the providers, so both stay in rotation.
+	ses.AssertNumberOfCalls(t, "SendEmail", 3)
+	smtp.AssertNumberOfCalls(t, "SendEmail", 3)
+}
+
+func TestFailoverImpl_VerifyEmail(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(ses, smtp *mocks.SESIface)
+		wantErr bool
+	}{
+		{
+			name: "there is to return success with the first provider",
+			setup: func(ses, smtp *mocks.SESIface) {
+				ses.On("VerifyEmail", "user@example.com").Return(nil).Once()
+			},
+			wantErr: false,
+		},
+		{
+			name: "there is to return rejected without trying the next provider",
+			setup: func(ses, smtp *mocks.SESIface) {
+				ses.On("VerifyEmail", "user@example.com").Return(fmt.Errorf("%w: InvalidParameterValue", ErrRejected)).Once()
+			},
+			wantErr: true,
+		},
+		{
+			name: "there is to return success with the second provider",
+			setup: func(ses, smtp *mocks.SESIface) {
+				ses.On("VerifyEmail", "user@example.com").Return(errors.New("throttling")).Once()
+				smtp.On("VerifyEmail", "user@example.com").Return(nil).Once()
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ses := mocks.NewSESIface(t)
+			smtp := mocks.NewSESIface(t)
+			logger := mocks.NewLogger(t)
+			logger.On("Errorf", mock.Anything, mock.Anything).Return().Maybe()
+			tt.setup(ses, smtp)
+
+			failover := NewFailoverImpl(value.EmailBreaker{Threshold: 1, Cooldown: time.Minute}, logger,
+				Sender{Name: value.SESEmailProvider, Provider: ses},
+				Sender{Name: value.SMTPEmailProvider, Provider: smtp},
+			)
+
+			err := failover.VerifyEmail("user@example.com")
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
+
+func TestFailoverImpl_VerifyEmailSkipsBreaker(t *testing.T) {
+	ses := mocks.NewSESIface(t)
+	logger := mocks.NewLogger(t)
+
+	failover := NewFailoverImpl(value.EmailBreaker{Threshold: 1, Cooldown: time.Minute}, logger,
+		Sender{Name: value.SESEmailProvider, Provider: ses},
+	)
+
+	ses.On("VerifyEmail", "not an email").Return(fmt.Errorf("%w: InvalidParameterValue", ErrRejected))
+	for i := 0; i < 3; i++ {
+		assert.ErrorIs(t, failover.VerifyEmail("not an email"), ErrRejected)
+	}
+
+	ses.On("VerifyEmail", "user@example.com").Return(nil)
+	assert.NoError(t, failover.VerifyEmail("user@example.com"))
+}
@@ -1,13 +1,18 @@
 package email
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/ses"
 	"github.com/gurodrigues-dev/notifier-app/internal/entity"
+	"github.com/gurodrigues-dev/notifier-app/internal/value"
 	"github.com/spf13/viper"
 )
 
@@ -33,7 +38,11 @@ func NewSesImpl() *SesImpl {
 	}
 }
 
-func (sesImpl *SesImpl) SendEmail(email *entity.Email) error {
+func (sesImpl *SesImpl) SendEmailVia(ctx context.Context, email *entity.Email) (string, error) {
+	return value.SESEmailProvider, sesImpl.SendEmail(ctx, email)
+}
+
+func (sesImpl *SesImpl) SendEmail(ctx context.Context, email *entity.Email) error {
 	emailInput := &ses.SendEmailInput{
 		Destination: &ses.Destination{
 			ToAddresses: []*string{aws.String(email.Recipient)},
@@ -51,10 +60,10 @@ func (sesImpl *SesImpl) SendEmail(email *entity.Email) error {
 		Source: aws.String(viper.GetString("AWS_SES_EMAIL_FROM")),
 	}
 
-	_, err := sesImpl.ses.SendEmail(emailInput)
+	_, err := sesImpl.ses.SendEmailWithContext(ctx, emailInput)
 
 	if err != nil {
-		return err
+		return sesError(err)
 	}
 
 	return nil
@@ -68,8 +77,21 @@ func (sesImpl *SesImpl) VerifyEmail(email string) error {
 	_, err := sesImpl.ses.VerifyEmailIdentity(verifyEmailInput)
 
 	if err != nil {
-		return err
+		return sesError(err)
 	}
 
 	return nil
 }
+
+// sesError marks the errors SES returns for the email itself, rather than
+// for the service failing, as ErrRejected.
+func sesError(err error) error {
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) {
+		switch awsErr.Code() {
+		case ses.ErrCodeMessageRejected, "InvalidParameterValue":
+			return fmt.Errorf("%w: %w", ErrRejected, err)
+		}
+	}
+	return err
+}
@@ -0,0 +1,253 @@
+package email
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gurodrigues-dev/notifier-app/internal/entity"
+	"github.com/gurodrigues-dev/notifier-app/internal/value"
+	"github.com/gurodrigues-dev/notifier-app/pkg/stringcommon"
+)
+
+// SmtpImpl sends emails through any SMTP relay, such as a corporate server or
+// MailHog when running locally. Connections are kept open between sends, up
+// to the configured pool size, so a burst of emails does not pay the
+// handshake for each one.
+type SmtpImpl struct {
+	config value.SMTP
+	addr   string
+	pool   chan *smtpConn
+}
+
+type smtpConn struct {
+	conn   net.Conn
+	client *smtp.Client
+}
+
+func NewSmtpImpl(config value.SMTP) *SmtpImpl {
+	return &SmtpImpl{
+		config: config,
+		addr:   net.JoinHostPort(config.Host, strconv.Itoa(config.Port)),
+		pool:   make(chan *smtpConn, config.PoolSize),
+	}
+}
+
+func (smtpImpl *SmtpImpl) SendEmail(ctx context.Context, email *entity.Email) error {
+	conn, err := smtpImpl.get(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = smtpImpl.send(conn, email)
+	if err != nil {
+		conn.close()
+		return err
+	}
+
+	smtpImpl.put(conn)
+	return nil
+}
+
+func (smtpImpl *SmtpImpl) SendEmailVia(ctx context.Context, email *entity.Email) (string, error) {
+	return value.SMTPEmailProvider, smtpImpl.SendEmail(ctx, email)
+}
+
+// VerifyEmail accepts only a bare address, since the target is sent as is in
+// RCPT TO and the To header: a display name or comment, which ParseAddress
+// allows, would be refused by the server on every send.
+func (smtpImpl *SmtpImpl) VerifyEmail(email string) error {
+	addr, err := mail.ParseAddress(email)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrRejected, err)
+	}
+	if addr.Address != email {
+		return fmt.Errorf("%w: %q is not a bare email address", ErrRejected, email)
+	}
+	return nil
+}
+
+// Close quits every idle connection in the pool.
+func (smtpImpl *SmtpImpl) Close() error {
+	for {
+		select {
+		case conn := <-smtpImpl.pool:
+			smtpImpl.setDeadline(context.Background(), conn.conn)
+			conn.quit()
+		default:
+			return nil
+		}
+	}
+}
+
+func (smtpImpl *SmtpImpl) send(conn *smtpConn, email *entity.Email) error {
+	err := conn.client.Mail(smtpImpl.config.From)
+	if err != nil {
+		return err
+	}
+
+	err = conn.client.Rcpt(email.Recipient)
+	if err != nil {
+		return smtpError(err)
+	}
+
+	writer, err := conn.client.Data()
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(writer, smtpImpl.message(email))
+	if err != nil {
+		writer.Close()
+		return err
+	}
+
+	return smtpError(writer.Close())
+}
+
+// smtpError marks permanent replies to the recipient or the message, such
+// as an unknown mailbox or refused content, as ErrRejected. Transient 4xx
+// replies and connection errors are the server failing.
+func smtpError(err error) error {
+	var replyErr *textproto.Error
+	if errors.As(err, &replyErr) && replyErr.Code >= 500 {
+		return fmt.Errorf("%w: %w", ErrRejected, err)
+	}
+	return err
+}
+
+func (smtpImpl *SmtpImpl) message(email *entity.Email) string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "From: %s\r\n", smtpImpl.config.From)
+	fmt.Fprintf(&builder, "To: %s\r\n", email.Recipient)
+	fmt.Fprintf(&builder, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", email.Subject))
+	fmt.Fprintf(&builder, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	builder.WriteString("MIME-Version: 1.0\r\n")
+	builder.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	builder.WriteString("\r\n")
+	builder.WriteString(email.Body)
+	return builder.String()
+}
+
+// get takes an idle connection that still answers, or dials a new one when
+// the pool is empty.
+func (smtpImpl *SmtpImpl) get(ctx context.Context) (*smtpConn, error) {
+	for {
+		select {
+		case conn := <-smtpImpl.pool:
+			smtpImpl.setDeadline(ctx, conn.conn)
+			if conn.client.Noop() == nil {
+				return conn, nil
+			}
+			conn.close()
+		default:
+			return smtpImpl.dial(ctx)
+		}
+	}
+}
+
+// put resets a connection for the next email and keeps it, unless the pool
+// is already full.
+func (smtpImpl *SmtpImpl) put(conn *smtpConn) {
+	if conn.client.Reset() != nil {
+		conn.close()
+		return
+	}
+
+	select {
+	case smtpImpl.pool <- conn:
+	default:
+		conn.quit()
+	}
+}
+
+func (smtpImpl *SmtpImpl) dial(ctx context.Context) (*smtpConn, error) {
+	dialer := &net.Dialer{Timeout: smtpImpl.config.Timeout}
+
+	var (
+		conn net.Conn
+		err  error
+	)
+	if smtpImpl.config.TLS == value.SMTPImplicitTLS {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: smtpImpl.tlsConfig()}).DialContext(ctx, "tcp", smtpImpl.addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", smtpImpl.addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to smtp server %s: %w", smtpImpl.addr, err)
+	}
+	smtpImpl.setDeadline(ctx, conn)
+
+	client, err := smtp.NewClient(conn, smtpImpl.config.Host)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	pooled := &smtpConn{conn: conn, client: client}
+
+	err = smtpImpl.handshake(client)
+	if err != nil {
+		pooled.close()
+		return nil, err
+	}
+
+	return pooled, nil
+}
+
+func (smtpImpl *SmtpImpl) handshake(client *smtp.Client) error {
+	if smtpImpl.config.TLS == value.SMTPStartTLS {
+		ok, _ := client.Extension("STARTTLS")
+		if !ok {
+			return errors.New("smtp server does not support STARTTLS")
+		}
+
+		err := client.StartTLS(smtpImpl.tlsConfig())
+		if err != nil {
+			return err
+		}
+	}
+
+	if !stringcommon.Empty(smtpImpl.config.Username) {
+		auth := smtp.PlainAuth("", smtpImpl.config.Username, smtpImpl.config.Password, smtpImpl.config.Host)
+		err := client.Auth(auth)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (smtpImpl *SmtpImpl) tlsConfig() *tls.Config {
+	return &tls.Config{ServerName: smtpImpl.config.Host}
+}
+
+// setDeadline bounds every command on the connection by the context, or by
+// the configured timeout when the context has no deadline.
+func (smtpImpl *SmtpImpl) setDeadline(ctx context.Context, conn net.Conn) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(smtpImpl.config.Timeout)
+	}
+	conn.SetDeadline(deadline)
+}
+
+func (conn *smtpConn) quit() {
+	if conn.client.Quit() != nil {
+		conn.close()
+	}
+}
+
+func (conn *smtpConn) close() {
+	conn.client.Close()
+}
@@ -0,0 +1,171 @@
+package email
+
+import (
+	"context"
+	"net"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gurodrigues-dev/notifier-app/internal/entity"
+	"github.com/gurodrigues-dev/notifier-app/internal/value"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeSMTPServer accepts plaintext SMTP sessions and keeps every message it
+// receives, like MailHog does.
+type fakeSMTPServer struct {
+	listener net.Listener
+
+	mu          sync.Mutex
+	connections int
+	messages    []string
+}
+
+func newFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := &fakeSMTPServer{listener: listener}
+	go server.serve()
+	t.Cleanup(func() { listener.Close() })
+
+	return server
+}
+
+func (s *fakeSMTPServer) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *fakeSMTPServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		s.connections++
+		s.mu.Unlock()
+
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeSMTPServer) handle(conn net.Conn) {
+	defer conn.Close()
+
+	text := textproto.NewConn(conn)
+	text.PrintfLine("220 localhost ready")
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+
+		switch command := strings.ToUpper(strings.Fields(line)[0]); command {
+		case "EHLO", "HELO":
+			text.PrintfLine("250 localhost")
+		case "DATA":
+			text.PrintfLine("354 go ahead")
+			body, err := text.ReadDotLines()
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.messages = append(s.messages, strings.Join(body, "\n"))
+			s.mu.Unlock()
+			text.PrintfLine("250 queued")
+		case "RCPT":
+			if strings.Contains(line, "unknown@") {
+				text.PrintfLine("550 mailbox unavailable")
+				continue
+			}
+			text.PrintfLine("250 ok")
+		case "QUIT":
+			text.PrintfLine("221 bye")
+			return
+		default:
+			text.PrintfLine("250 ok")
+		}
+	}
+}
+
+func TestSmtpImpl_SendEmail(t *testing.T) {
+	server := newFakeSMTPServer(t)
+
+	smtpImpl := NewSmtpImpl(value.SMTP{
+		Host:     "127.0.0.1",
+		Port:     server.port(),
+		From:     "notifier@example.com",
+		TLS:      value.SMTPNoTLS,
+		PoolSize: 1,
+		Timeout:  value.GetSMTP().Timeout,
+	})
+	defer smtpImpl.Close()
+
+	for _, subject := range []string{"Payment Success", "Pagamento concluído"} {
+		err := smtpImpl.SendEmail(context.Background(), &entity.Email{
+			Recipient: "user@example.com",
+			Subject:   subject,
+			Body:      "There was a new transaction",
+		})
+		assert.NoError(t, err)
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	assert.Equal(t, 1, server.connections)
+	assert.Len(t, server.messages, 2)
+	assert.Contains(t, server.messages[0], "Subject: Payment Success")
+	assert.Contains(t, server.messages[1], "Subject: =?utf-8?q?Pagamento_conclu=C3=ADdo?=")
+	assert.Contains(t, server.messages[1], "There was a new transaction")
+}
+
+func TestSmtpImpl_SendEmailRequiresStartTLS(t *testing.T) {
+	server := newFakeSMTPServer(t)
+
+	smtpImpl := NewSmtpImpl(value.SMTP{
+		Host:     "127.0.0.1",
+		Port:     server.port(),
+		From:     "notifier@example.com",
+		TLS:      value.SMTPStartTLS,
+		PoolSize: 1,
+		Timeout:  value.GetSMTP().Timeout,
+	})
+	defer smtpImpl.Close()
+
+	err := smtpImpl.SendEmail(context.Background(), &entity.Email{Recipient: "user@example.com"})
+	assert.Error(t, err)
+}
+
+func TestSmtpImpl_SendEmailRejected(t *testing.T) {
+	server := newFakeSMTPServer(t)
+
+	smtpImpl := NewSmtpImpl(value.SMTP{
+		Host:     "127.0.0.1",
+		Port:     server.port(),
+		From:     "notifier@example.com",
+		TLS:      value.SMTPNoTLS,
+		PoolSize: 1,
+		Timeout:  value.GetSMTP().Timeout,
+	})
+	defer smtpImpl.Close()
+
+	err := smtpImpl.SendEmail(context.Background(), &entity.Email{Recipient: "unknown@example.com"})
+	assert.ErrorIs(t, err, ErrRejected)
+}
+
+func TestSmtpImpl_VerifyEmail(t *testing.T) {
+	smtpImpl := NewSmtpImpl(value.SMTP{})
+
+	assert.NoError(t, smtpImpl.VerifyEmail("user@example.com"))
+	assert.ErrorIs(t, smtpImpl.VerifyEmail("not an email"), ErrRejected)
+	assert.ErrorIs(t, smtpImpl.VerifyEmail("John <john@example.com>"), ErrRejected)
+	assert.ErrorIs(t, smtpImpl.VerifyEmail("john@example.com (c)"), ErrRejected)
+	assert.ErrorIs(t, smtpImpl.VerifyEmail(" john@example.com"), ErrRejected)
+}
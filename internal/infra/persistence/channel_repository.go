@@ -5,6 +5,10 @@ import (
 	"github.com/gurodrigues-dev/notifier-app/internal/infra/contracts"
 )
 
+// channelColumns leaves the secret out of every channel read, so it cannot
+// reach queue messages or API responses by accident.
+const channelColumns = `id, platform, target_id, "group"`
+
 type ChannelRepositoryImpl struct {
 	Postgres contracts.PostgresIface
 }
@@ -18,7 +22,7 @@ func (cr ChannelRepositoryImpl) CreateChannel(channel *entity.Channel) (*entity.
 
 func (cr ChannelRepositoryImpl) GetByID(id string) (*entity.Channel, error) {
 	var channel entity.Channel
-	err := cr.Postgres.Client().Where("id = ?", id).First(&channel).Error
+	err := cr.Postgres.Client().Select(channelColumns).Where("id = ?", id).First(&channel).Error
 	if err != nil {
 		return nil, err
 	}
@@ -27,7 +31,7 @@ func (cr ChannelRepositoryImpl) GetByID(id string) (*entity.Channel, error) {
 
 func (cr ChannelRepositoryImpl) GetByIDs(ids []string) ([]entity.Channel, error) {
 	var channels []entity.Channel
-	err := cr.Postgres.Client().Where("id IN (?)", ids).Find(&channels).Error
+	err := cr.Postgres.Client().Select(channelColumns).Where("id IN (?)", ids).Find(&channels).Error
 	if err != nil {
 		return nil, err
 	}
@@ -36,7 +40,7 @@ func (cr ChannelRepositoryImpl) GetByIDs(ids []string) ([]entity.Channel, error)
 
 func (cr ChannelRepositoryImpl) GetByGroup(group string) ([]entity.Channel, error) {
 	var channels []entity.Channel
-	err := cr.Postgres.Client().Where(`"group" = ?`, group).Find(&channels).Error
+	err := cr.Postgres.Client().Select(channelColumns).Where(`"group" = ?`, group).Find(&channels).Error
 	if err != nil {
 		return nil, err
 	}
@@ -45,7 +49,7 @@ func (cr ChannelRepositoryImpl) GetByGroup(group string) ([]entity.Channel, erro
 
 func (cr ChannelRepositoryImpl) GetByGroups(groups []string) ([]entity.Channel, error) {
 	var channels []entity.Channel
-	err := cr.Postgres.Client().Where(`"group" IN (?)`, groups).Find(&channels).Error
+	err := cr.Postgres.Client().Select(channelColumns).Where(`"group" IN (?)`, groups).Find(&channels).Error
 	if err != nil {
 		return nil, err
 	}
@@ -54,7 +58,7 @@ func (cr ChannelRepositoryImpl) GetByGroups(groups []string) ([]entity.Channel,
 
 func (cr ChannelRepositoryImpl) GetByPlatform(platform string) ([]entity.Channel, error) {
 	var channels []entity.Channel
-	err := cr.Postgres.Client().Where("platform = ?", platform).Find(&channels).Error
+	err := cr.Postgres.Client().Select(channelColumns).Where("platform = ?", platform).Find(&channels).Error
 	if err != nil {
 		return nil, err
 	}
@@ -64,3 +68,12 @@ func (cr ChannelRepositoryImpl) GetByPlatform(platform string) ([]entity.Channel
 func (cr ChannelRepositoryImpl) DeleteByID(id string) error {
 	return cr.Postgres.Client().Where("id = ?", id).Delete(&entity.Channel{}).Error
 }
+
+func (cr ChannelRepositoryImpl) GetSecretByID(id int) (string, error) {
+	var channel entity.Channel
+	err := cr.Postgres.Client().Select("secret").Where("id = ?", id).First(&channel).Error
+	if err != nil {
+		return "", err
+	}
+	return channel.Secret, nil
+}
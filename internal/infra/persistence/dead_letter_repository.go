@@ -0,0 +1,76 @@
+package persistence
+
+import (
+	"time"
+
+	"github.com/gurodrigues-dev/notifier-app/internal/domain/repository"
+	"github.com/gurodrigues-dev/notifier-app/internal/entity"
+	"github.com/gurodrigues-dev/notifier-app/internal/infra/contracts"
+)
+
+type DeadLetterRepositoryImpl struct {
+	Postgres contracts.PostgresIface
+}
+
+// CreateDeadLetter stores a dead letter. It returns repository.ErrDuplicateKey
+// when the same attempt of the notification is already stored and was not
+// re-driven.
+func (dr DeadLetterRepositoryImpl) CreateDeadLetter(deadLetter *entity.DeadLetter) error {
+	return translateError(dr.Postgres.Client().Create(deadLetter).Error)
+}
+
+// GetPendingDeadLetter returns the dead letter not re-driven yet of the given
+// attempt of a notification, or nil when there is none.
+func (dr DeadLetterRepositoryImpl) GetPendingDeadLetter(uuid string, attempts int64) (*entity.DeadLetter, error) {
+	var deadLetters []entity.DeadLetter
+	err := dr.Postgres.Client().
+		Where("uuid = ? AND attempts = ? AND redriven_at IS NULL", uuid, attempts).
+		Limit(1).
+		Find(&deadLetters).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(deadLetters) == 0 {
+		return nil, nil
+	}
+	return &deadLetters[0], nil
+}
+
+func (dr DeadLetterRepositoryImpl) ListDeadLetters(limit, offset int) ([]entity.DeadLetter, error) {
+	var deadLetters []entity.DeadLetter
+	err := dr.Postgres.Client().Order("id DESC").Limit(limit).Offset(offset).Find(&deadLetters).Error
+	if err != nil {
+		return nil, err
+	}
+	return deadLetters, nil
+}
+
+func (dr DeadLetterRepositoryImpl) GetDeadLetterByID(id string) (*entity.DeadLetter, error) {
+	var deadLetter entity.DeadLetter
+	err := dr.Postgres.Client().Where("id = ?", id).First(&deadLetter).Error
+	if err != nil {
+		return nil, err
+	}
+	return &deadLetter, nil
+}
+
+// MarkRedriven claims the dead letter for a re-drive. It returns
+// repository.ErrAlreadyRedriven when it was already re-driven, so concurrent
+// re-drives publish it only once.
+func (dr DeadLetterRepositoryImpl) MarkRedriven(id int, redrivenAt time.Time) error {
+	result := dr.Postgres.Client().Model(&entity.DeadLetter{}).
+		Where("id = ? AND redriven_at IS NULL", id).
+		Update("redriven_at", redrivenAt)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repository.ErrAlreadyRedriven
+	}
+	return nil
+}
+
+// UnmarkRedriven releases the claim of a re-drive that could not be published.
+func (dr DeadLetterRepositoryImpl) UnmarkRedriven(id int) error {
+	return dr.Postgres.Client().Model(&entity.DeadLetter{}).Where("id = ?", id).Update("redriven_at", nil).Error
+}
@@ -0,0 +1,48 @@
+package persistence
+
+import (
+	"time"
+
+	"github.com/gurodrigues-dev/notifier-app/internal/entity"
+	"github.com/gurodrigues-dev/notifier-app/internal/infra/contracts"
+	"github.com/gurodrigues-dev/notifier-app/internal/value"
+)
+
+type DeliveryRepositoryImpl struct {
+	Postgres contracts.PostgresIface
+}
+
+// StartDelivery marks the channel as pending and counts a new attempt,
+// creating the delivery on the first one.
+func (dr DeliveryRepositoryImpl) StartDelivery(uuid string, channelID int) error {
+	now := time.Now()
+	return dr.Postgres.Client().Exec(`
+		INSERT INTO deliveries (uuid, channel_id, status, attempts, last_error, created_at, updated_at)
+		VALUES (?, ?, ?, 1, '', ?, ?)
+		ON CONFLICT (uuid, channel_id) DO UPDATE
+		SET status = EXCLUDED.status, attempts = deliveries.attempts + 1, updated_at = EXCLUDED.updated_at`,
+		uuid, channelID, value.PendingStatus, now, now,
+	).Error
+}
+
+// FinishDelivery records the outcome of the attempt and the upstream provider
+// that delivered it, if any.
+func (dr DeliveryRepositoryImpl) FinishDelivery(uuid string, channelID int, status, lastError, provider string) error {
+	return dr.Postgres.Client().Model(&entity.Delivery{}).
+		Where("uuid = ? AND channel_id = ?", uuid, channelID).
+		Updates(map[string]any{
+			"status":     status,
+			"last_error": lastError,
+			"provider":   provider,
+			"updated_at": time.Now(),
+		}).Error
+}
+
+func (dr DeliveryRepositoryImpl) GetDeliveriesByUUID(uuid string) ([]entity.Delivery, error) {
+	var deliveries []entity.Delivery
+	err := dr.Postgres.Client().Where("uuid = ?", uuid).Order("channel_id").Find(&deliveries).Error
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
@@ -0,0 +1,19 @@
+package persistence
+
+import (
+	"errors"
+
+	"github.com/gurodrigues-dev/notifier-app/internal/domain/repository"
+	"github.com/lib/pq"
+)
+
+const uniqueViolation = "23505"
+
+// translateError maps driver errors to the ones the domain knows about.
+func translateError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return repository.ErrDuplicateKey
+	}
+	return err
+}
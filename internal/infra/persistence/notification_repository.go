@@ -1,8 +1,12 @@
 package persistence
 
 import (
+	"time"
+
+	"github.com/gurodrigues-dev/notifier-app/internal/domain/repository"
 	"github.com/gurodrigues-dev/notifier-app/internal/entity"
 	"github.com/gurodrigues-dev/notifier-app/internal/infra/contracts"
+	"github.com/gurodrigues-dev/notifier-app/internal/value"
 )
 
 type NotificationRepositoryImpl struct {
@@ -21,3 +25,180 @@ func (nr NotificationRepositoryImpl) GetNotificationByID(id string) (*entity.Not
 	}
 	return &notification, nil
 }
+
+// CreateRecordWithOutbox stores the notification record and its queue message
+// in a single transaction, so a notification is never accepted without being
+// eventually published. It returns repository.ErrDuplicateKey when a record
+// with the same UUID already exists.
+func (nr NotificationRepositoryImpl) CreateRecordWithOutbox(record *entity.NotificationRecord, message *entity.OutboxMessage) error {
+	tx := nr.Postgres.Client().Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	err := tx.Create(record).Error
+	if err != nil {
+		tx.Rollback()
+		return translateError(err)
+	}
+
+	err = tx.Create(message).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// CreateRecordsWithOutbox stores a batch of records, each with the queue
+// message at the same index, in a single transaction. Records whose UUID
+// already exists are skipped along with their message, and their UUIDs are
+// returned.
+func (nr NotificationRepositoryImpl) CreateRecordsWithOutbox(records []*entity.NotificationRecord, messages []*entity.OutboxMessage) ([]string, error) {
+	tx := nr.Postgres.Client().Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	var duplicates []string
+	now := time.Now()
+	for i, record := range records {
+		record.CreatedAt = now
+		record.UpdatedAt = now
+
+		result := tx.Exec(
+			`INSERT INTO notifications (uuid, body, status, retries, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?)
+			ON CONFLICT (uuid) DO NOTHING`,
+			record.UUID, string(record.Body), record.Status, record.Retries, record.CreatedAt, record.UpdatedAt,
+		)
+		if result.Error != nil {
+			tx.Rollback()
+			return nil, result.Error
+		}
+		if result.RowsAffected == 0 {
+			duplicates = append(duplicates, record.UUID)
+			continue
+		}
+
+		err := tx.Create(messages[i]).Error
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	err := tx.Commit().Error
+	if err != nil {
+		return nil, err
+	}
+
+	return duplicates, nil
+}
+
+func (nr NotificationRepositoryImpl) UpdateRecordStatus(uuid, status string, retries int64) error {
+	return nr.Postgres.Client().Model(&entity.NotificationRecord{}).
+		Where("uuid = ?", uuid).
+		Updates(map[string]any{
+			"status":     status,
+			"retries":    retries,
+			"updated_at": time.Now(),
+		}).Error
+}
+
+// AdvanceRecordStatus moves the record to status to only while it is still in
+// one of the statuses from, so a late update never overwrites a newer state.
+func (nr NotificationRepositoryImpl) AdvanceRecordStatus(uuid string, from []string, to string) error {
+	return nr.Postgres.Client().Model(&entity.NotificationRecord{}).
+		Where("uuid = ? AND status IN (?)", uuid, from).
+		Updates(map[string]any{
+			"status":     to,
+			"updated_at": time.Now(),
+		}).Error
+}
+
+// CancelScheduled marks a scheduled record as cancelled and removes its
+// outbox row. It returns repository.ErrNotScheduled when the record is not
+// scheduled anymore or the relay has already claimed its row.
+func (nr NotificationRepositoryImpl) CancelScheduled(uuid string) error {
+	tx := nr.Postgres.Client().Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	now := time.Now()
+	result := tx.Model(&entity.NotificationRecord{}).
+		Where("uuid = ? AND status = ?", uuid, value.ScheduledStatus).
+		Updates(map[string]any{
+			"status":     value.CancelledStatus,
+			"updated_at": now,
+		})
+	if result.Error != nil {
+		tx.Rollback()
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return repository.ErrNotScheduled
+	}
+
+	result = tx.Where("key = ? AND sent_at IS NULL AND (locked_until IS NULL OR locked_until < ?)", uuid, now).
+		Delete(&entity.OutboxMessage{})
+	if result.Error != nil {
+		tx.Rollback()
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return repository.ErrNotScheduled
+	}
+
+	return tx.Commit().Error
+}
+
+// RescheduleRecord moves the publication of a scheduled record to sendAt. It
+// returns repository.ErrNotScheduled when the record is not scheduled anymore
+// or the relay has already claimed its row.
+func (nr NotificationRepositoryImpl) RescheduleRecord(uuid string, sendAt time.Time) error {
+	tx := nr.Postgres.Client().Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	now := time.Now()
+	result := tx.Model(&entity.NotificationRecord{}).
+		Where("uuid = ? AND status = ?", uuid, value.ScheduledStatus).
+		Update("updated_at", now)
+	if result.Error != nil {
+		tx.Rollback()
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return repository.ErrNotScheduled
+	}
+
+	result = tx.Model(&entity.OutboxMessage{}).
+		Where("key = ? AND sent_at IS NULL AND (locked_until IS NULL OR locked_until < ?)", uuid, now).
+		Update("available_at", sendAt)
+	if result.Error != nil {
+		tx.Rollback()
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return repository.ErrNotScheduled
+	}
+
+	return tx.Commit().Error
+}
+
+func (nr NotificationRepositoryImpl) GetRecordByUUID(uuid string) (*entity.NotificationRecord, error) {
+	var record entity.NotificationRecord
+	err := nr.Postgres.Client().Where("uuid = ?", uuid).Order("id DESC").First(&record).Error
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
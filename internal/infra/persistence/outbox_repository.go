@@ -0,0 +1,57 @@
+package persistence
+
+import (
+	"sort"
+	"time"
+
+	"github.com/gurodrigues-dev/notifier-app/internal/entity"
+	"github.com/gurodrigues-dev/notifier-app/internal/infra/contracts"
+)
+
+type OutboxRepositoryImpl struct {
+	Postgres contracts.PostgresIface
+}
+
+// ClaimPending locks up to limit unsent messages that are due for lease and
+// returns them, oldest first. Rows claimed by another relay are skipped, and a claim whose
+// relay died is picked up again once its lease is over.
+func (ob OutboxRepositoryImpl) ClaimPending(limit int, lease time.Duration) ([]entity.OutboxMessage, error) {
+	var messages []entity.OutboxMessage
+	err := ob.Postgres.Client().Raw(`
+		UPDATE outbox
+		SET locked_until = ?, attempts = attempts + 1
+		WHERE id IN (
+			SELECT id FROM outbox
+			WHERE sent_at IS NULL AND available_at <= ? AND (locked_until IS NULL OR locked_until < ?)
+			ORDER BY id
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		time.Now().Add(lease), time.Now(), time.Now(), limit,
+	).Scan(&messages).Error
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(messages, func(i, j int) bool {
+		return messages[i].ID < messages[j].ID
+	})
+
+	return messages, nil
+}
+
+func (ob OutboxRepositoryImpl) MarkSent(id int, at time.Time) error {
+	return ob.Postgres.Client().Model(&entity.OutboxMessage{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"sent_at":    at,
+			"last_error": "",
+		}).Error
+}
+
+func (ob OutboxRepositoryImpl) MarkFailed(id int, lastError string) error {
+	return ob.Postgres.Client().Model(&entity.OutboxMessage{}).
+		Where("id = ?", id).
+		Update("last_error", lastError).Error
+}
@@ -6,4 +6,7 @@ type PostgresRepositories struct {
 	AuthRepository         repository.AuthRepository
 	NotificationRepository repository.NotificationRepository
 	ChannelRepository      repository.ChannelRepository
+	DeadLetterRepository   repository.DeadLetterRepository
+	DeliveryRepository     repository.DeliveryRepository
+	OutboxRepository       repository.OutboxRepository
 }
@@ -0,0 +1,44 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/gurodrigues-dev/notifier-app/internal/entity"
+	"github.com/gurodrigues-dev/notifier-app/internal/infra/contracts"
+	"github.com/gurodrigues-dev/notifier-app/internal/value"
+)
+
+type DiscordProvider struct {
+	webhook contracts.Webhook
+}
+
+func NewDiscordProvider(webhook contracts.Webhook) *DiscordProvider {
+	return &DiscordProvider{
+		webhook: webhook,
+	}
+}
+
+func (dp *DiscordProvider) Platform() string {
+	return value.DiscordPlatform
+}
+
+func (dp *DiscordProvider) ValidateTarget(channel *entity.Channel) error {
+	return validateWebhookURL(channel)
+}
+
+func (dp *DiscordProvider) Render(channel entity.Channel, notification *entity.Notification) ([]byte, error) {
+	return json.Marshal(map[string]string{
+		"content": fmt.Sprintf("%s: %s", notification.Title, notification.Message),
+	})
+}
+
+func (dp *DiscordProvider) Send(ctx context.Context, channel entity.Channel, notification *entity.Notification) error {
+	body, err := dp.Render(channel, notification)
+	if err != nil {
+		return err
+	}
+
+	return postJSON(ctx, dp.webhook, dp.Platform(), channel.TargetID, body)
+}
@@ -0,0 +1,56 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/gurodrigues-dev/notifier-app/internal/entity"
+	"github.com/gurodrigues-dev/notifier-app/internal/infra/contracts"
+	"github.com/gurodrigues-dev/notifier-app/internal/value"
+)
+
+type EmailProvider struct {
+	ses contracts.SESIface
+}
+
+func NewEmailProvider(ses contracts.SESIface) *EmailProvider {
+	return &EmailProvider{
+		ses: ses,
+	}
+}
+
+func (ep *EmailProvider) Platform() string {
+	return value.EmailPlatform
+}
+
+func (ep *EmailProvider) ValidateTarget(channel *entity.Channel) error {
+	return ep.ses.VerifyEmail(channel.TargetID)
+}
+
+func (ep *EmailProvider) Render(channel entity.Channel, notification *entity.Notification) ([]byte, error) {
+	return []byte(notification.Message), nil
+}
+
+func (ep *EmailProvider) Send(ctx context.Context, channel entity.Channel, notification *entity.Notification) error {
+	_, err := ep.SendVia(ctx, channel, notification)
+	return err
+}
+
+// SendVia sends the email and returns the name of the email provider that
+// delivered it, or an empty name when the sender does not report one.
+func (ep *EmailProvider) SendVia(ctx context.Context, channel entity.Channel, notification *entity.Notification) (string, error) {
+	body, err := ep.Render(channel, notification)
+	if err != nil {
+		return "", err
+	}
+
+	email := &entity.Email{
+		Recipient: channel.TargetID,
+		Subject:   notification.Title,
+		Body:      string(body),
+	}
+
+	if router, ok := ep.ses.(contracts.EmailRouter); ok {
+		return router.SendEmailVia(ctx, email)
+	}
+	return "", ep.ses.SendEmail(ctx, email)
+}
@@ -0,0 +1,37 @@
+package provider
+
+import (
+	"fmt"
+
+	"github.com/gurodrigues-dev/notifier-app/internal/infra/contracts"
+)
+
+// Registry keeps the providers known by the application, indexed by platform.
+// Providers are registered once during setup, so reads do not need locking.
+type Registry struct {
+	providers map[string]contracts.Provider
+}
+
+func NewRegistry(providers ...contracts.Provider) *Registry {
+	registry := &Registry{
+		providers: make(map[string]contracts.Provider),
+	}
+
+	for _, provider := range providers {
+		registry.Register(provider)
+	}
+
+	return registry
+}
+
+func (r *Registry) Register(provider contracts.Provider) {
+	r.providers[provider.Platform()] = provider
+}
+
+func (r *Registry) Get(platform string) (contracts.Provider, error) {
+	provider, ok := r.providers[platform]
+	if !ok {
+		return nil, fmt.Errorf("invalid platform: %s", platform)
+	}
+	return provider, nil
+}
@@ -0,0 +1,122 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gurodrigues-dev/notifier-app/internal/domain/repository"
+	"github.com/gurodrigues-dev/notifier-app/internal/entity"
+	"github.com/gurodrigues-dev/notifier-app/internal/infra/contracts"
+	"github.com/gurodrigues-dev/notifier-app/internal/value"
+)
+
+const webhookSecretBytes = 32
+
+// SignedWebhookProvider posts notifications as JSON to any URL. Each request
+// is signed with the secret generated for its channel, so the receiver can
+// check it came from us and is recent.
+type SignedWebhookProvider struct {
+	webhook           contracts.Webhook
+	channelRepository repository.ChannelRepository
+}
+
+func NewSignedWebhookProvider(webhook contracts.Webhook, channelRepository repository.ChannelRepository) *SignedWebhookProvider {
+	return &SignedWebhookProvider{
+		webhook:           webhook,
+		channelRepository: channelRepository,
+	}
+}
+
+// webhookEnvelope is the versioned body of a signed webhook. Only the
+// notification itself is sent, not the other channels it goes to.
+type webhookEnvelope struct {
+	Version      string              `json:"version"`
+	Type         string              `json:"type"`
+	Notification webhookNotification `json:"notification"`
+}
+
+type webhookNotification struct {
+	UUID      string       `json:"uuid"`
+	Title     string       `json:"title"`
+	Message   string       `json:"message"`
+	Event     entity.Event `json:"event"`
+	ExpiresAt *time.Time   `json:"expires_at,omitempty"`
+}
+
+func (wp *SignedWebhookProvider) Platform() string {
+	return value.WebhookPlatform
+}
+
+func (wp *SignedWebhookProvider) ValidateTarget(channel *entity.Channel) error {
+	return validateWebhookURL(channel)
+}
+
+func (wp *SignedWebhookProvider) GenerateSecret() (string, error) {
+	secret := make([]byte, webhookSecretBytes)
+	_, err := rand.Read(secret)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
+
+func (wp *SignedWebhookProvider) Render(channel entity.Channel, notification *entity.Notification) ([]byte, error) {
+	return json.Marshal(webhookEnvelope{
+		Version: value.WebhookEnvelopeVersion,
+		Type:    "notification",
+		Notification: webhookNotification{
+			UUID:      notification.UUID,
+			Title:     notification.Title,
+			Message:   notification.Message,
+			Event:     notification.Event,
+			ExpiresAt: notification.ExpiresAt,
+		},
+	})
+}
+
+func (wp *SignedWebhookProvider) Send(ctx context.Context, channel entity.Channel, notification *entity.Notification) error {
+	secret, err := wp.channelRepository.GetSecretByID(channel.ID)
+	if err != nil {
+		return fmt.Errorf("error getting webhook secret of channel %d: %w", channel.ID, err)
+	}
+
+	body, err := wp.Render(channel, notification)
+	if err != nil {
+		return err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	resp, err := wp.webhook.PostWithHeaders(ctx, channel.TargetID, map[string]string{
+		"Content-Type":               "application/json",
+		value.WebhookTimestampHeader: timestamp,
+		value.WebhookSignatureHeader: "sha256=" + signWebhook(secret, timestamp, body),
+	}, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s returned status code %d", wp.Platform(), resp.StatusCode)
+	}
+
+	return nil
+}
+
+// signWebhook returns the hex HMAC-SHA256 of "timestamp.body". Signing the
+// timestamp along with the body lets receivers reject replayed requests.
+func signWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,80 @@
+package provider
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gurodrigues-dev/notifier-app/internal/entity"
+	"github.com/gurodrigues-dev/notifier-app/internal/infra/webhook"
+	"github.com/gurodrigues-dev/notifier-app/internal/value"
+	"github.com/gurodrigues-dev/notifier-app/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignedWebhookProvider_Send(t *testing.T) {
+	var (
+		headers http.Header
+		body    []byte
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	channelRepository := mocks.NewChannelRepository(t)
+	channelRepository.On("GetSecretByID", 3).Return("secret", nil)
+
+	provider := NewSignedWebhookProvider(&webhook.DefaultClient{}, channelRepository)
+	err := provider.Send(context.Background(), entity.Channel{ID: 3, TargetID: server.URL}, &entity.Notification{
+		UUID:  "550e8400-e29b-41d4-a716-446655440000",
+		Title: "Payment Success",
+		Channels: map[int]entity.Channel{
+			3: {ID: 3, TargetID: server.URL},
+			4: {ID: 4, TargetID: "other@example.com"},
+		},
+	})
+	assert.NoError(t, err)
+
+	timestamp := headers.Get(value.WebhookTimestampHeader)
+	sentAt, err := strconv.ParseInt(timestamp, 10, 64)
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), time.Unix(sentAt, 0), time.Minute)
+
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	assert.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), headers.Get(value.WebhookSignatureHeader))
+	assert.Equal(t, "application/json", headers.Get("Content-Type"))
+
+	var envelope map[string]any
+	assert.NoError(t, json.Unmarshal(body, &envelope))
+	assert.Equal(t, value.WebhookEnvelopeVersion, envelope["version"])
+	assert.Equal(t, "550e8400-e29b-41d4-a716-446655440000", envelope["notification"].(map[string]any)["uuid"])
+	assert.NotContains(t, string(body), "other@example.com")
+}
+
+func TestSignedWebhookProvider_SendStatusError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	channelRepository := mocks.NewChannelRepository(t)
+	channelRepository.On("GetSecretByID", 3).Return("secret", nil)
+
+	provider := NewSignedWebhookProvider(&webhook.DefaultClient{}, channelRepository)
+	err := provider.Send(context.Background(), entity.Channel{ID: 3, TargetID: server.URL}, &entity.Notification{})
+
+	assert.EqualError(t, err, "webhook returned status code 401")
+}
@@ -0,0 +1,44 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/gurodrigues-dev/notifier-app/internal/entity"
+	"github.com/gurodrigues-dev/notifier-app/internal/infra/contracts"
+	"github.com/gurodrigues-dev/notifier-app/internal/value"
+)
+
+type SlackProvider struct {
+	webhook contracts.Webhook
+}
+
+func NewSlackProvider(webhook contracts.Webhook) *SlackProvider {
+	return &SlackProvider{
+		webhook: webhook,
+	}
+}
+
+func (sp *SlackProvider) Platform() string {
+	return value.SlackPlatform
+}
+
+func (sp *SlackProvider) ValidateTarget(channel *entity.Channel) error {
+	return validateWebhookURL(channel)
+}
+
+func (sp *SlackProvider) Render(channel entity.Channel, notification *entity.Notification) ([]byte, error) {
+	return json.Marshal(map[string]string{
+		"text": fmt.Sprintf("%s: %s", notification.Title, notification.Message),
+	})
+}
+
+func (sp *SlackProvider) Send(ctx context.Context, channel entity.Channel, notification *entity.Notification) error {
+	body, err := sp.Render(channel, notification)
+	if err != nil {
+		return err
+	}
+
+	return postJSON(ctx, sp.webhook, sp.Platform(), channel.TargetID, body)
+}
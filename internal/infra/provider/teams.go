@@ -0,0 +1,113 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gurodrigues-dev/notifier-app/internal/entity"
+	"github.com/gurodrigues-dev/notifier-app/internal/infra/contracts"
+	"github.com/gurodrigues-dev/notifier-app/internal/value"
+)
+
+const (
+	adaptiveCardContentType = "application/vnd.microsoft.card.adaptive"
+	adaptiveCardSchema      = "http://adaptivecards.io/schemas/adaptive-card.json"
+	adaptiveCardVersion     = "1.4"
+)
+
+// TeamsProvider posts notifications to Microsoft Teams incoming webhooks as
+// Adaptive Cards.
+type TeamsProvider struct {
+	webhook contracts.Webhook
+}
+
+func NewTeamsProvider(webhook contracts.Webhook) *TeamsProvider {
+	return &TeamsProvider{
+		webhook: webhook,
+	}
+}
+
+type teamsMessage struct {
+	Type        string            `json:"type"`
+	Attachments []teamsAttachment `json:"attachments"`
+}
+
+type teamsAttachment struct {
+	ContentType string       `json:"contentType"`
+	Content     adaptiveCard `json:"content"`
+}
+
+type adaptiveCard struct {
+	Schema  string         `json:"$schema"`
+	Type    string         `json:"type"`
+	Version string         `json:"version"`
+	Body    []adaptiveItem `json:"body"`
+}
+
+type adaptiveItem struct {
+	Type   string         `json:"type"`
+	Text   string         `json:"text,omitempty"`
+	Weight string         `json:"weight,omitempty"`
+	Size   string         `json:"size,omitempty"`
+	Wrap   bool           `json:"wrap,omitempty"`
+	Facts  []adaptiveFact `json:"facts,omitempty"`
+}
+
+type adaptiveFact struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+func (tp *TeamsProvider) Platform() string {
+	return value.TeamsPlatform
+}
+
+func (tp *TeamsProvider) ValidateTarget(channel *entity.Channel) error {
+	return validateWebhookURL(channel)
+}
+
+// Render builds a card with the title, the message and the event details as
+// a fact set.
+func (tp *TeamsProvider) Render(channel entity.Channel, notification *entity.Notification) ([]byte, error) {
+	event := notification.Event
+
+	return json.Marshal(teamsMessage{
+		Type: "message",
+		Attachments: []teamsAttachment{
+			{
+				ContentType: adaptiveCardContentType,
+				Content: adaptiveCard{
+					Schema:  adaptiveCardSchema,
+					Type:    "AdaptiveCard",
+					Version: adaptiveCardVersion,
+					Body: []adaptiveItem{
+						{Type: "TextBlock", Text: notification.Title, Weight: "Bolder", Size: "Medium", Wrap: true},
+						{Type: "TextBlock", Text: notification.Message, Wrap: true},
+						{
+							Type: "FactSet",
+							Facts: []adaptiveFact{
+								{Title: "Event", Value: event.Name},
+								{Title: "Category", Value: event.Category},
+								{Title: "Amount", Value: fmt.Sprintf("%s %.2f", event.Currency, float64(event.CostCents)/100)},
+								{Title: "Requester", Value: event.Requester},
+								{Title: "Receiver", Value: event.Receiver},
+								{Title: "Date", Value: time.Unix(event.Timestamp, 0).UTC().Format(time.RFC3339)},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+}
+
+func (tp *TeamsProvider) Send(ctx context.Context, channel entity.Channel, notification *entity.Notification) error {
+	body, err := tp.Render(channel, notification)
+	if err != nil {
+		return err
+	}
+
+	return postJSON(ctx, tp.webhook, tp.Platform(), channel.TargetID, body)
+}
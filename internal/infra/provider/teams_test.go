@@ -0,0 +1,122 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gurodrigues-dev/notifier-app/internal/entity"
+	"github.com/gurodrigues-dev/notifier-app/internal/infra/webhook"
+	"github.com/stretchr/testify/assert"
+)
+
+type testAdaptiveCard struct {
+	Type        string `json:"type"`
+	Attachments []struct {
+		ContentType string `json:"contentType"`
+		Content     struct {
+			Type    string `json:"type"`
+			Version string `json:"version"`
+			Body    []struct {
+				Type  string `json:"type"`
+				Text  string `json:"text"`
+				Facts []struct {
+					Title string `json:"title"`
+					Value string `json:"value"`
+				} `json:"facts"`
+			} `json:"body"`
+		} `json:"content"`
+	} `json:"attachments"`
+}
+
+func testTeamsNotification() *entity.Notification {
+	return &entity.Notification{
+		UUID:    "550e8400-e29b-41d4-a716-446655440000",
+		Title:   "Order Confirmation",
+		Message: "Your order #12345 has been confirmed.",
+		Event: entity.Event{
+			Name:      "OrderPlaced",
+			Currency:  "BRL",
+			Requester: "system",
+			Receiver:  "user",
+			Category:  "ecommerce",
+			Timestamp: 1716720000,
+			CostCents: 5050,
+		},
+	}
+}
+
+func TestTeamsProvider_Render(t *testing.T) {
+	provider := NewTeamsProvider(&webhook.DefaultClient{})
+
+	body, err := provider.Render(entity.Channel{}, testTeamsNotification())
+	assert.NoError(t, err)
+
+	var card testAdaptiveCard
+	assert.NoError(t, json.Unmarshal(body, &card))
+	assert.Equal(t, "message", card.Type)
+	assert.Len(t, card.Attachments, 1)
+
+	attachment := card.Attachments[0]
+	assert.Equal(t, "application/vnd.microsoft.card.adaptive", attachment.ContentType)
+	assert.Equal(t, "AdaptiveCard", attachment.Content.Type)
+	assert.Equal(t, "1.4", attachment.Content.Version)
+	assert.Equal(t, "Order Confirmation", attachment.Content.Body[0].Text)
+	assert.Equal(t, "Your order #12345 has been confirmed.", attachment.Content.Body[1].Text)
+
+	facts := make(map[string]string)
+	for _, fact := range attachment.Content.Body[2].Facts {
+		facts[fact.Title] = fact.Value
+	}
+	assert.Equal(t, map[string]string{
+		"Event":     "OrderPlaced",
+		"Category":  "ecommerce",
+		"Amount":    "BRL 50.50",
+		"Requester": "system",
+		"Receiver":  "user",
+		"Date":      "2024-05-26T10:40:00Z",
+	}, facts)
+}
+
+func TestTeamsProvider_Send(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{
+			name:    "there is to return success",
+			status:  http.StatusOK,
+			wantErr: false,
+		},
+		{
+			name:    "there is to return webhook error",
+			status:  http.StatusBadRequest,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				contentType string
+				card        testAdaptiveCard
+			)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				contentType = r.Header.Get("Content-Type")
+				_ = json.NewDecoder(r.Body).Decode(&card)
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			provider := NewTeamsProvider(&webhook.DefaultClient{})
+			err := provider.Send(context.Background(), entity.Channel{ID: 1, TargetID: server.URL}, testTeamsNotification())
+
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, "application/json", contentType)
+			assert.Len(t, card.Attachments, 1)
+			assert.Equal(t, "Order Confirmation", card.Attachments[0].Content.Body[0].Text)
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gurodrigues-dev/notifier-app/internal/domain/repository"
+	"github.com/gurodrigues-dev/notifier-app/internal/entity"
+	"github.com/gurodrigues-dev/notifier-app/internal/infra/contracts"
+	"github.com/gurodrigues-dev/notifier-app/internal/value"
+)
+
+var (
+	telegramChatID   = regexp.MustCompile(`^(-?\d+|@[A-Za-z][A-Za-z0-9_]{4,})$`)
+	telegramBotToken = regexp.MustCompile(`^\d+:[A-Za-z0-9_-]+$`)
+
+	// telegramEscaper escapes the characters MarkdownV2 reserves, so the text
+	// of a notification is never read as formatting.
+	telegramEscaper = strings.NewReplacer(
+		`\`, `\\`, "_", `\_`, "*", `\*`, "[", `\[`, "]", `\]`, "(", `\(`, ")", `\)`,
+		"~", `\~`, "`", "\\`", ">", `\>`, "#", `\#`, "+", `\+`, "-", `\-`, "=", `\=`,
+		"|", `\|`, "{", `\{`, "}", `\}`, ".", `\.`, "!", `\!`,
+	)
+)
+
+// TelegramProvider sends notifications to Telegram chats through the Bot API.
+// The channel target is the chat ID and its secret the bot token, which is
+// looked up on every send so it never travels in queue messages.
+type TelegramProvider struct {
+	webhook           contracts.Webhook
+	channelRepository repository.ChannelRepository
+	apiURL            string
+}
+
+func NewTelegramProvider(webhook contracts.Webhook, channelRepository repository.ChannelRepository, apiURL string) *TelegramProvider {
+	return &TelegramProvider{
+		webhook:           webhook,
+		channelRepository: channelRepository,
+		apiURL:            strings.TrimSuffix(apiURL, "/"),
+	}
+}
+
+func (tp *TelegramProvider) Platform() string {
+	return value.TelegramPlatform
+}
+
+func (tp *TelegramProvider) ValidateTarget(channel *entity.Channel) error {
+	if !telegramChatID.MatchString(channel.TargetID) {
+		return fmt.Errorf("invalid telegram chat id: %s", channel.TargetID)
+	}
+
+	return nil
+}
+
+// ValidateSecret checks the bot token the client gives as the channel secret.
+func (tp *TelegramProvider) ValidateSecret(secret string) error {
+	if !telegramBotToken.MatchString(secret) {
+		return errors.New("invalid telegram bot token")
+	}
+
+	return nil
+}
+
+func (tp *TelegramProvider) Render(channel entity.Channel, notification *entity.Notification) ([]byte, error) {
+	return json.Marshal(map[string]string{
+		"chat_id":    channel.TargetID,
+		"text":       fmt.Sprintf("*%s*\n%s", escapeMarkdownV2(notification.Title), escapeMarkdownV2(notification.Message)),
+		"parse_mode": "MarkdownV2",
+	})
+}
+
+func (tp *TelegramProvider) Send(ctx context.Context, channel entity.Channel, notification *entity.Notification) error {
+	token, err := tp.channelRepository.GetSecretByID(channel.ID)
+	if err != nil {
+		return fmt.Errorf("error getting telegram bot token of channel %d: %w", channel.ID, err)
+	}
+
+	body, err := tp.Render(channel, notification)
+	if err != nil {
+		return err
+	}
+
+	err = postJSON(ctx, tp.webhook, tp.Platform(), fmt.Sprintf("%s/bot%s/sendMessage", tp.apiURL, token), body)
+	if err != nil && token != "" {
+		// The token is part of the URL, which transport errors quote.
+		return errors.New(strings.ReplaceAll(err.Error(), token, "<token>"))
+	}
+
+	return err
+}
+
+func escapeMarkdownV2(text string) string {
+	return telegramEscaper.Replace(text)
+}
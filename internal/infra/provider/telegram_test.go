@@ -0,0 +1,135 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gurodrigues-dev/notifier-app/internal/entity"
+	"github.com/gurodrigues-dev/notifier-app/internal/infra/webhook"
+	"github.com/gurodrigues-dev/notifier-app/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTelegramProvider_Send(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{
+			name:    "there is to return success",
+			status:  http.StatusOK,
+			wantErr: false,
+		},
+		{
+			name:    "there is to return bot api error",
+			status:  http.StatusBadRequest,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				path    string
+				message map[string]string
+			)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				path = r.URL.Path
+				_ = json.NewDecoder(r.Body).Decode(&message)
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			channelRepository := mocks.NewChannelRepository(t)
+			channelRepository.On("GetSecretByID", 7).Return("123456:ABC-def_1", nil)
+
+			provider := NewTelegramProvider(&webhook.DefaultClient{}, channelRepository, server.URL+"/")
+			err := provider.Send(context.Background(), entity.Channel{ID: 7, TargetID: "-100123"}, &entity.Notification{
+				Title:   "Payment #42",
+				Message: "Paid 1.50 (BRL) to user_name!",
+			})
+
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, "/bot123456:ABC-def_1/sendMessage", path)
+			assert.Equal(t, "-100123", message["chat_id"])
+			assert.Equal(t, "MarkdownV2", message["parse_mode"])
+			assert.Equal(t, "*Payment \\#42*\nPaid 1\\.50 \\(BRL\\) to user\\_name\\!", message["text"])
+		})
+	}
+}
+
+func TestTelegramProvider_SendHidesToken(t *testing.T) {
+	channelRepository := mocks.NewChannelRepository(t)
+	channelRepository.On("GetSecretByID", 7).Return("123456:ABC-def_1", nil)
+
+	provider := NewTelegramProvider(&webhook.DefaultClient{}, channelRepository, "http://127.0.0.1:0")
+	err := provider.Send(context.Background(), entity.Channel{ID: 7, TargetID: "-100123"}, &entity.Notification{})
+
+	assert.Error(t, err)
+	assert.NotContains(t, err.Error(), "123456:ABC-def_1")
+}
+
+func TestTelegramProvider_ValidateTarget(t *testing.T) {
+	tests := []struct {
+		name    string
+		channel entity.Channel
+		wantErr bool
+	}{
+		{
+			name:    "there is to return success with chat id",
+			channel: entity.Channel{TargetID: "-100123", Secret: "123456:ABC-def_1"},
+			wantErr: false,
+		},
+		{
+			name:    "there is to return success with channel username",
+			channel: entity.Channel{TargetID: "@payments", Secret: "123456:ABC-def_1"},
+			wantErr: false,
+		},
+		{
+			name:    "there is to return invalid chat id",
+			channel: entity.Channel{TargetID: "payments", Secret: "123456:ABC-def_1"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := NewTelegramProvider(&webhook.DefaultClient{}, mocks.NewChannelRepository(t), "")
+			err := provider.ValidateTarget(&tt.channel)
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
+
+func TestTelegramProvider_ValidateSecret(t *testing.T) {
+	tests := []struct {
+		name    string
+		secret  string
+		wantErr bool
+	}{
+		{
+			name:    "there is to return success",
+			secret:  "123456:ABC-def_1",
+			wantErr: false,
+		},
+		{
+			name:    "there is to return missing bot token",
+			secret:  "",
+			wantErr: true,
+		},
+		{
+			name:    "there is to return invalid bot token",
+			secret:  "not a token",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := NewTelegramProvider(&webhook.DefaultClient{}, mocks.NewChannelRepository(t), "")
+			err := provider.ValidateSecret(tt.secret)
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
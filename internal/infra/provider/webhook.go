@@ -0,0 +1,38 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/gurodrigues-dev/notifier-app/internal/entity"
+	"github.com/gurodrigues-dev/notifier-app/internal/infra/contracts"
+)
+
+func validateWebhookURL(channel *entity.Channel) error {
+	target, err := url.ParseRequestURI(channel.TargetID)
+	if err != nil {
+		return fmt.Errorf("invalid %s webhook url: %w", channel.Platform, err)
+	}
+
+	if target.Scheme != "https" && target.Scheme != "http" {
+		return fmt.Errorf("invalid %s webhook url scheme: %s", channel.Platform, target.Scheme)
+	}
+
+	return nil
+}
+
+func postJSON(ctx context.Context, webhook contracts.Webhook, platform, url string, body []byte) error {
+	resp, err := webhook.Post(ctx, url, "application/json", bytes.NewBuffer(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s webhook returned status code %d", platform, resp.StatusCode)
+	}
+
+	return nil
+}
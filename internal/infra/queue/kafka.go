@@ -2,62 +2,388 @@ package queue
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
+	"strconv"
+	"sync"
 	"time"
 
+	"github.com/gurodrigues-dev/notifier-app/internal/entity"
+	"github.com/gurodrigues-dev/notifier-app/internal/infra/contracts"
+	"github.com/gurodrigues-dev/notifier-app/internal/value"
+	"github.com/gurodrigues-dev/notifier-app/pkg/keyedpool"
+	"github.com/gurodrigues-dev/notifier-app/pkg/stringcommon"
 	"github.com/segmentio/kafka-go"
 )
 
+const (
+	minHandlerBackoff = time.Second
+	maxHandlerBackoff = 30 * time.Second
+)
+
 type KafkaImpl struct {
-	brokers []string
+	brokers      []string
+	workers      int
+	buffer       int
+	drainTimeout time.Duration
+	attempts     int
+	producer     value.KafkaProducer
+	compression  kafka.Compression
+	requiredAcks kafka.RequiredAcks
+
+	mu      sync.Mutex
+	writers map[string]*kafka.Writer
 }
 
-func NewKafkaImpl(brokers []string) *KafkaImpl {
+// NewKafkaImpl creates a Kafka queue whose consumers run the handler on
+// workers goroutines, each holding at most buffer messages in memory, and
+// give the messages in flight drainTimeout to finish on shutdown before
+// cancelling them. A message whose handler fails
+// attempts times is dead-lettered. Messages are produced with a long-lived
+// writer per topic configured by producer.
+func NewKafkaImpl(brokers []string, workers, buffer int, drainTimeout time.Duration, attempts int, producer value.KafkaProducer) *KafkaImpl {
+	compression, err := parseCompression(producer.Compression)
+	if err != nil {
+		log.Fatalf("invalid kafka producer config: %v", err)
+	}
+
+	requiredAcks, err := parseRequiredAcks(producer.RequiredAcks)
+	if err != nil {
+		log.Fatalf("invalid kafka producer config: %v", err)
+	}
+
 	return &KafkaImpl{
-		brokers: brokers,
+		brokers:      brokers,
+		workers:      workers,
+		buffer:       buffer,
+		drainTimeout: drainTimeout,
+		attempts:     attempts,
+		producer:     producer,
+		compression:  compression,
+		requiredAcks: requiredAcks,
+		writers:      make(map[string]*kafka.Writer),
 	}
 }
 
-func (k *KafkaImpl) Produce(topic, message string) error {
-	writer := kafka.NewWriter(kafka.WriterConfig{
-		Brokers:  k.brokers,
-		Topic:    topic,
-		Balancer: &kafka.LeastBytes{},
-	})
+// Produce writes message to topic and returns once the broker acknowledges
+// it. Its callers commit offsets or update the database on success, so a
+// write must never be reported before it is known to have succeeded.
+func (k *KafkaImpl) Produce(topic string, message contracts.Message) error {
+	return k.ProduceBatch(topic, []contracts.Message{message})
+}
 
-	defer writer.Close()
+// ProduceBatch writes messages to topic in a single call, so they share the
+// writer batches instead of waiting for each other's acknowledgement, and
+// returns once the broker acknowledges all of them.
+func (k *KafkaImpl) ProduceBatch(topic string, messages []contracts.Message) error {
+	return k.writer(topic).WriteMessages(context.Background(), toKafkaMessages(messages)...)
+}
 
-	return writer.WriteMessages(context.Background(), kafka.Message{
-		Key:   []byte(time.Now().Format(time.RFC3339)),
-		Value: []byte(message),
-	})
+func toKafkaMessages(messages []contracts.Message) []kafka.Message {
+	batch := make([]kafka.Message, 0, len(messages))
+	for _, message := range messages {
+		headers := make([]kafka.Header, 0, len(message.Headers))
+		for key, value := range message.Headers {
+			headers = append(headers, kafka.Header{Key: key, Value: []byte(value)})
+		}
+
+		batch = append(batch, kafka.Message{
+			Key:     []byte(message.Key),
+			Value:   []byte(message.Value),
+			Headers: headers,
+		})
+	}
+
+	return batch
+}
+
+// Close flushes and closes every writer, waiting for the messages still in
+// their batches to be written.
+func (k *KafkaImpl) Close() error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	var errs []error
+	for topic, writer := range k.writers {
+		err := writer.Close()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("closing writer of %s: %w", topic, err))
+		}
+		delete(k.writers, topic)
+	}
+
+	return errors.Join(errs...)
 }
 
-func (k *KafkaImpl) Consumer(topic, group string, handler func(message string)) error {
+// writer returns the writer of topic, creating it on first use.
+func (k *KafkaImpl) writer(topic string) *kafka.Writer {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	writer, ok := k.writers[topic]
+	if ok {
+		return writer
+	}
+
+	writer = &kafka.Writer{
+		Addr:         kafka.TCP(k.brokers...),
+		Topic:        topic,
+		Balancer:     &kafka.LeastBytes{},
+		BatchSize:    k.producer.BatchSize,
+		BatchTimeout: k.producer.BatchTimeout,
+		Compression:  k.compression,
+		RequiredAcks: k.requiredAcks,
+	}
+	k.writers[topic] = writer
+
+	return writer
+}
+
+func parseCompression(compression string) (kafka.Compression, error) {
+	switch compression {
+	case "", "none":
+		return 0, nil
+	case "gzip":
+		return kafka.Gzip, nil
+	case "snappy":
+		return kafka.Snappy, nil
+	case "lz4":
+		return kafka.Lz4, nil
+	case "zstd":
+		return kafka.Zstd, nil
+	default:
+		return 0, fmt.Errorf("unknown compression %q", compression)
+	}
+}
+
+func parseRequiredAcks(requiredAcks string) (kafka.RequiredAcks, error) {
+	switch requiredAcks {
+	case "all":
+		return kafka.RequireAll, nil
+	case "one":
+		return kafka.RequireOne, nil
+	case "none":
+		return kafka.RequireNone, nil
+	default:
+		return 0, fmt.Errorf("unknown required acks %q", requiredAcks)
+	}
+}
+
+// Consumer handles the messages of topic on the worker pool until ctx is
+// cancelled. An offset is committed only after the handler returns nil for its
+// message and for every message before it in the partition; a failing handler
+// is retried with backoff, so a message is never dropped because of a
+// transient error. A message still failing after the configured attempts is
+// published as read to the dead-letter topic, so a permanent error does not
+// hold back its key and partition forever.
+//
+// Once ctx is cancelled no more messages are fetched, messages not started
+// yet are skipped, and the handlers in flight get the drain timeout to
+// finish. The context they run with is not cancelled until then, but carries
+// ctx.Done() as contracts.Stopping so they can stop waiting early. Once the
+// drain timeout is over their context is cancelled, cutting off the work
+// still in flight, and Consumer waits for them to return. It then commits what
+// was handled and closes the reader. Messages left unhandled are redelivered
+// to the next consumer of the group.
+func (k *KafkaImpl) Consumer(ctx context.Context, topic, group string, handler func(ctx context.Context, message contracts.Message) error) error {
 
 	reader := kafka.NewReader(kafka.ReaderConfig{
-		Brokers:        k.brokers,
-		GroupID:        group,
-		Topic:          topic,
-		MinBytes:       10e3,
-		MaxBytes:       10e6,
-		CommitInterval: time.Second,
+		Brokers:  k.brokers,
+		GroupID:  group,
+		Topic:    topic,
+		MinBytes: 10e3,
+		MaxBytes: 10e6,
 	})
+	defer reader.Close()
 
-	go func() {
-		log.Println("Kafka consumer started...")
-		defer reader.Close()
+	handlerCtx, cancelHandlers := context.WithCancel(contracts.WithStopping(context.WithoutCancel(ctx), ctx.Done()))
+	defer cancelHandlers()
 
-		for {
-			m, err := reader.ReadMessage(context.Background())
+	pool := keyedpool.New(k.workers, k.buffer)
+	offsets := newOffsetTracker(func(message kafka.Message) error {
+		return reader.CommitMessages(context.Background(), message)
+	})
+
+	log.Printf("Kafka consumer started on %s...", topic)
+	for {
+		m, err := reader.FetchMessage(ctx)
+		if ctx.Err() != nil {
+			break
+		}
+		if err != nil {
+			log.Printf("Kafka read error: %v", err)
+			continue
+		}
+		log.Println("Message received")
+		tracked := offsets.track(m)
+		pool.Submit(messageKey(m), k.job(ctx, handlerCtx, m, handler, func() {
+			err := offsets.done(tracked)
 			if err != nil {
-				log.Printf("Kafka read error: %v", err)
-				continue
+				log.Printf("Kafka commit error on %s[%d]@%d: %v", m.Topic, m.Partition, m.Offset, err)
 			}
-			log.Println("Message received")
-			handler(string(m.Value))
+		}))
+	}
+
+	k.drain(topic, pool, cancelHandlers)
+
+	return nil
+}
+
+// job returns the pool job that handles m and calls done once it is handled.
+// Messages still queued when ctx is cancelled are left uncommitted for the
+// next consumer instead of delaying the drain.
+func (k *KafkaImpl) job(ctx, handlerCtx context.Context, m kafka.Message, handler func(ctx context.Context, message contracts.Message) error, done func()) func() {
+	return func() {
+		if ctx.Err() != nil {
+			return
+		}
+		if !k.handle(ctx, handlerCtx, m, handler) {
+			return
 		}
+		done()
+	}
+}
+
+// drain waits for the jobs in the pool to finish. Once the drain timeout is
+// over it cancels the handlers and waits for them to return, so the reader,
+// and the stores the handler uses, are never closed under them.
+func (k *KafkaImpl) drain(topic string, pool *keyedpool.Pool, cancelHandlers context.CancelFunc) {
+	log.Printf("Kafka consumer on %s draining in-flight messages...", topic)
+	drained := make(chan struct{})
+	go func() {
+		pool.Close()
+		close(drained)
 	}()
 
-	return nil
+	select {
+	case <-drained:
+		log.Printf("Kafka consumer on %s drained", topic)
+	case <-time.After(k.drainTimeout):
+		log.Printf("Kafka consumer on %s drain timed out after %s, cancelling in-flight messages", topic, k.drainTimeout)
+		cancelHandlers()
+		<-drained
+	}
+}
+
+// handle runs the handler until it succeeds, waiting longer after every
+// failure up to maxHandlerBackoff, and dead-letters the message once the
+// attempts are exhausted. Messages of the dead-letter topic have nowhere else
+// to go, so they are retried until their handler succeeds. The handler runs
+// with handlerCtx; handle gives up once ctx is cancelled and reports whether
+// the message was handled.
+func (k *KafkaImpl) handle(ctx, handlerCtx context.Context, m kafka.Message, handler func(ctx context.Context, message contracts.Message) error) bool {
+	message := contracts.Message{
+		Key:     string(m.Key),
+		Value:   string(m.Value),
+		Headers: make(map[string]string, len(m.Headers)),
+	}
+	for _, header := range m.Headers {
+		message.Headers[header.Key] = string(header.Value)
+	}
+
+	var (
+		errs        []string
+		receivedAt  = time.Now()
+		backoff     = minHandlerBackoff
+		deadLetters = m.Topic == value.GetDeadLetterTopic()
+	)
+	for {
+		err := handler(handlerCtx, message)
+		if err == nil {
+			return true
+		}
+
+		if ctx.Err() != nil {
+			log.Printf("Kafka handler stopped on %s[%d]@%d: %v", m.Topic, m.Partition, m.Offset, err)
+			return false
+		}
+
+		if !deadLetters {
+			errs = append(errs, err.Error())
+		}
+		if !deadLetters && len(errs) >= k.attempts {
+			log.Printf("Kafka handler failed %d times on %s[%d]@%d, dead-lettering it: %v", len(errs), m.Topic, m.Partition, m.Offset, err)
+			return k.deadLetter(ctx, m, message, errs, receivedAt)
+		}
+
+		log.Printf("Kafka handler error on %s[%d]@%d, retrying in %s: %v", m.Topic, m.Partition, m.Offset, backoff, err)
+		if !sleep(ctx, backoff) {
+			return false
+		}
+		backoff = nextBackoff(backoff)
+	}
+}
+
+// deadLetter publishes a message its handler could not handle, with the
+// error of every attempt, to the dead-letter topic, whose consumer stores it
+// to be listed and re-driven. Like the dead letters the dispatcher stores, its
+// topic is the main one it is re-driven to. Publishing is retried until it
+// succeeds or ctx is cancelled, and reports whether it did.
+func (k *KafkaImpl) deadLetter(ctx context.Context, m kafka.Message, message contracts.Message, errs []string, receivedAt time.Time) bool {
+	serializedDeadLetter, err := stringcommon.SerializeToJSON(&entity.DeadLetter{
+		UUID:       message.Key,
+		Topic:      value.GetTopic(),
+		Payload:    message.Value,
+		Errors:     errs,
+		Attempts:   int64(len(errs)),
+		ReceivedAt: receivedAt,
+		FailedAt:   time.Now(),
+	})
+	if err != nil {
+		log.Printf("Kafka dead letter error on %s[%d]@%d: %v", m.Topic, m.Partition, m.Offset, err)
+		return false
+	}
+
+	deadLetter := contracts.Message{
+		Key:   message.Key,
+		Value: string(serializedDeadLetter),
+		Headers: map[string]string{
+			value.TraceIDHeader:       message.Headers[value.TraceIDHeader],
+			value.SchemaVersionHeader: value.NotificationSchemaVersion,
+		},
+	}
+
+	backoff := minHandlerBackoff
+	for {
+		err := k.Produce(value.GetDeadLetterTopic(), deadLetter)
+		if err == nil {
+			return true
+		}
+
+		log.Printf("Kafka dead letter error on %s[%d]@%d, retrying in %s: %v", m.Topic, m.Partition, m.Offset, backoff, err)
+		if !sleep(ctx, backoff) {
+			return false
+		}
+		backoff = nextBackoff(backoff)
+	}
+}
+
+// sleep waits for d and reports whether it did, returning early when ctx is
+// cancelled.
+func sleep(ctx context.Context, d time.Duration) bool {
+	select {
+	case <-time.After(d):
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func nextBackoff(backoff time.Duration) time.Duration {
+	backoff *= 2
+	if backoff > maxHandlerBackoff {
+		return maxHandlerBackoff
+	}
+	return backoff
+}
+
+// messageKey returns the key that orders a message: messages sharing it are
+// handled one at a time, in the order they were read. Messages without a key
+// keep the order of their partition.
+func messageKey(m kafka.Message) []byte {
+	if len(m.Key) > 0 {
+		return m.Key
+	}
+	return []byte(strconv.Itoa(m.Partition))
 }
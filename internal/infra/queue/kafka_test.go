@@ -0,0 +1,77 @@
+package queue
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gurodrigues-dev/notifier-app/internal/infra/contracts"
+	"github.com/gurodrigues-dev/notifier-app/pkg/keyedpool"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKafkaImpl_JobSkipsQueuedMessagesAfterCancel(t *testing.T) {
+	k := &KafkaImpl{attempts: 1, drainTimeout: time.Minute}
+	pool := keyedpool.New(1, 2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	release := make(chan struct{})
+
+	var handled, done atomic.Int32
+	handler := func(ctx context.Context, message contracts.Message) error {
+		handled.Add(1)
+		if message.Key == "first" {
+			<-release
+		}
+		return nil
+	}
+
+	pool.Submit([]byte("key"), k.job(ctx, context.Background(), kafka.Message{Key: []byte("first")}, handler, func() { done.Add(1) }))
+	pool.Submit([]byte("key"), k.job(ctx, context.Background(), kafka.Message{Key: []byte("second")}, handler, func() { done.Add(1) }))
+
+	// The first message is in flight when the consumer stops, the second is
+	// still queued behind it.
+	assert.Eventually(t, func() bool { return handled.Load() == 1 }, time.Second, time.Millisecond)
+	cancel()
+	close(release)
+	pool.Close()
+
+	assert.Equal(t, int32(1), handled.Load(), "the queued message must be skipped")
+	assert.Equal(t, int32(1), done.Load(), "only the message in flight is committed")
+}
+
+func TestKafkaImpl_DrainCancelsHandlersAfterTimeout(t *testing.T) {
+	k := &KafkaImpl{attempts: 1, drainTimeout: 50 * time.Millisecond}
+	pool := keyedpool.New(1, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	handlerCtx, cancelHandlers := context.WithCancel(contracts.WithStopping(context.WithoutCancel(ctx), ctx.Done()))
+	defer cancelHandlers()
+
+	var done atomic.Int32
+	started := make(chan struct{})
+	pool.Submit([]byte("key"), k.job(ctx, handlerCtx, kafka.Message{Key: []byte("key")}, func(ctx context.Context, message contracts.Message) error {
+		close(started)
+		// A delivery that does not finish before the drain timeout.
+		<-ctx.Done()
+		return ctx.Err()
+	}, func() { done.Add(1) }))
+
+	<-started
+	cancel()
+
+	drained := make(chan struct{})
+	go func() {
+		k.drain("notifications", pool, cancelHandlers)
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+	case <-time.After(time.Second):
+		t.Fatal("drain did not cancel the handler after its timeout")
+	}
+	assert.Equal(t, int32(0), done.Load(), "a cancelled message is not committed")
+}
@@ -0,0 +1,71 @@
+package queue
+
+import (
+	"sync"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// offsetTracker commits offsets only once every message before them in the
+// same partition has been handled, so a message still in flight on a slow
+// worker is never skipped by a commit made for a faster one.
+type offsetTracker struct {
+	mu      sync.Mutex
+	pending map[int][]*trackedMessage
+	commit  func(message kafka.Message) error
+}
+
+type trackedMessage struct {
+	message kafka.Message
+	done    bool
+}
+
+func newOffsetTracker(commit func(message kafka.Message) error) *offsetTracker {
+	return &offsetTracker{
+		pending: make(map[int][]*trackedMessage),
+		commit:  commit,
+	}
+}
+
+// track registers a fetched message. Messages must be tracked in the order
+// they were fetched.
+func (ot *offsetTracker) track(message kafka.Message) *trackedMessage {
+	ot.mu.Lock()
+	defer ot.mu.Unlock()
+
+	tracked := &trackedMessage{
+		message: kafka.Message{
+			Topic:     message.Topic,
+			Partition: message.Partition,
+			Offset:    message.Offset,
+		},
+	}
+	ot.pending[message.Partition] = append(ot.pending[message.Partition], tracked)
+
+	return tracked
+}
+
+// done marks a message as handled and commits the highest offset of its
+// partition whose predecessors are all handled.
+func (ot *offsetTracker) done(tracked *trackedMessage) error {
+	ot.mu.Lock()
+	defer ot.mu.Unlock()
+
+	tracked.done = true
+
+	partition := tracked.message.Partition
+	queue := ot.pending[partition]
+
+	handled := 0
+	for handled < len(queue) && queue[handled].done {
+		handled++
+	}
+	if handled == 0 {
+		return nil
+	}
+
+	last := queue[handled-1].message
+	ot.pending[partition] = queue[handled:]
+
+	return ot.commit(last)
+}
@@ -0,0 +1,33 @@
+package queue
+
+import (
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOffsetTracker_Done(t *testing.T) {
+	var committed []int64
+	tracker := newOffsetTracker(func(message kafka.Message) error {
+		committed = append(committed, message.Offset)
+		return nil
+	})
+
+	first := tracker.track(kafka.Message{Partition: 0, Offset: 10})
+	second := tracker.track(kafka.Message{Partition: 0, Offset: 11})
+	third := tracker.track(kafka.Message{Partition: 0, Offset: 12})
+	other := tracker.track(kafka.Message{Partition: 1, Offset: 3})
+
+	assert.NoError(t, tracker.done(second))
+	assert.Empty(t, committed, "offset 11 must wait for offset 10")
+
+	assert.NoError(t, tracker.done(other))
+	assert.Equal(t, []int64{3}, committed, "partitions are committed independently")
+
+	assert.NoError(t, tracker.done(first))
+	assert.Equal(t, []int64{3, 11}, committed)
+
+	assert.NoError(t, tracker.done(third))
+	assert.Equal(t, []int64{3, 11, 12}, committed)
+}
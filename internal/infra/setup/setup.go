@@ -1,6 +1,7 @@
 package setup
 
 import (
+	"io"
 	"log"
 	"os"
 
@@ -11,8 +12,11 @@ import (
 	"github.com/gurodrigues-dev/notifier-app/internal/infra/email"
 	"github.com/gurodrigues-dev/notifier-app/internal/infra/logger"
 	"github.com/gurodrigues-dev/notifier-app/internal/infra/persistence"
+	"github.com/gurodrigues-dev/notifier-app/internal/infra/provider"
 	"github.com/gurodrigues-dev/notifier-app/internal/infra/queue"
+	"github.com/gurodrigues-dev/notifier-app/internal/infra/webhook"
 	"github.com/gurodrigues-dev/notifier-app/internal/metrics"
+	"github.com/gurodrigues-dev/notifier-app/internal/value"
 	"github.com/spf13/viper"
 )
 
@@ -45,14 +49,36 @@ func (s Setup) Repositories() {
 	s.app.Repositories.NotificationRepository = persistence.NotificationRepositoryImpl{Postgres: s.app.Postgres}
 	s.app.Repositories.AuthRepository = persistence.AuthRepositoryImpl{Postgres: s.app.Postgres}
 	s.app.Repositories.ChannelRepository = persistence.ChannelRepositoryImpl{Postgres: s.app.Postgres}
+	s.app.Repositories.DeadLetterRepository = persistence.DeadLetterRepositoryImpl{Postgres: s.app.Postgres}
+	s.app.Repositories.DeliveryRepository = persistence.DeliveryRepositoryImpl{Postgres: s.app.Postgres}
+	s.app.Repositories.OutboxRepository = persistence.OutboxRepositoryImpl{Postgres: s.app.Postgres}
 }
 
 func (s Setup) Cache() {
 	s.app.Cache = cache.NewCacheImpl()
 }
 
+// Email builds the email senders named in EMAIL_PROVIDER. When more than one
+// is configured they are chained, each taking over when the previous fails.
 func (s Setup) Email() {
-	s.app.Email = email.NewSesImpl()
+	var senders []email.Sender
+	for _, provider := range value.GetEmailProviders() {
+		switch provider {
+		case value.SESEmailProvider:
+			senders = append(senders, email.Sender{Name: provider, Provider: email.NewSesImpl()})
+		case value.SMTPEmailProvider:
+			senders = append(senders, email.Sender{Name: provider, Provider: email.NewSmtpImpl(value.GetSMTP())})
+		default:
+			log.Fatalf("unknown email provider: %s", provider)
+		}
+	}
+
+	if len(senders) == 1 {
+		s.app.Email = senders[0].Provider
+		return
+	}
+
+	s.app.Email = email.NewFailoverImpl(value.GetEmailBreaker(), s.app.Logger, senders...)
 }
 
 func (s Setup) Logger(taskname string) {
@@ -60,13 +86,68 @@ func (s Setup) Logger(taskname string) {
 }
 
 func (s *Setup) Queue() {
-	s.app.Queue = queue.NewKafkaImpl([]string{viper.GetString("KAFKA_BROKER")})
+	s.app.Queue = queue.NewKafkaImpl(
+		[]string{viper.GetString("KAFKA_BROKER")},
+		value.GetConsumerWorkers(),
+		value.GetConsumerBuffer(),
+		value.GetDrainTimeout(),
+		value.GetHandlerAttempts(),
+		value.GetKafkaProducer(),
+	)
 }
 
 func (s Setup) Metrics() {
 	s.app.Metrics = metrics.NewPrometheusImpl()
 }
 
+func (s Setup) Webhook() {
+	s.app.Webhook = &webhook.DefaultClient{}
+}
+
+func (s Setup) Providers() {
+	s.app.Providers = provider.NewRegistry(
+		provider.NewEmailProvider(s.app.Email),
+		provider.NewSlackProvider(s.app.Webhook),
+		provider.NewDiscordProvider(s.app.Webhook),
+		provider.NewTeamsProvider(s.app.Webhook),
+		provider.NewTelegramProvider(s.app.Webhook, s.app.Repositories.ChannelRepository, value.GetTelegramAPIURL()),
+		provider.NewSignedWebhookProvider(s.app.Webhook, s.app.Repositories.ChannelRepository),
+	)
+}
+
 func (s Setup) Finish() {
 	infra.App = *s.app
 }
+
+// Close releases the connections opened by the setup. The queue is closed
+// first so pending messages are written before the stores they came from go
+// away.
+func (s Setup) Close() {
+	if s.app.Queue != nil {
+		err := s.app.Queue.Close()
+		if err != nil {
+			log.Printf("error closing queue: %v", err)
+		}
+	}
+
+	if closer, ok := s.app.Email.(io.Closer); ok {
+		err := closer.Close()
+		if err != nil {
+			log.Printf("error closing email: %v", err)
+		}
+	}
+
+	if s.app.Cache != nil {
+		err := s.app.Cache.Close()
+		if err != nil {
+			log.Printf("error closing cache: %v", err)
+		}
+	}
+
+	if s.app.Postgres != nil {
+		err := s.app.Postgres.Close()
+		if err != nil {
+			log.Printf("error closing database: %v", err)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package webhook
 
 import (
+	"context"
 	"io"
 	"net/http"
 
@@ -9,8 +10,20 @@ import (
 
 type DefaultClient struct{}
 
-func (d *DefaultClient) Post(url, contentType string, body io.Reader) (*contracts.HTTPResponse, error) {
-	resp, err := http.Post(url, contentType, body)
+func (d *DefaultClient) Post(ctx context.Context, url, contentType string, body io.Reader) (*contracts.HTTPResponse, error) {
+	return d.PostWithHeaders(ctx, url, map[string]string{"Content-Type": contentType}, body)
+}
+
+func (d *DefaultClient) PostWithHeaders(ctx context.Context, url string, headers map[string]string, body io.Reader) (*contracts.HTTPResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, body)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
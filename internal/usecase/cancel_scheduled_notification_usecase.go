@@ -0,0 +1,47 @@
+package usecase
+
+import (
+	"errors"
+
+	"github.com/gurodrigues-dev/notifier-app/internal/domain/repository"
+	"github.com/gurodrigues-dev/notifier-app/internal/infra/contracts"
+	"github.com/gurodrigues-dev/notifier-app/internal/value"
+)
+
+type CancelScheduledNotificationUsecase struct {
+	notificationRepository repository.NotificationRepository
+	logger                 contracts.Logger
+}
+
+func NewCancelScheduledNotificationUsecase(
+	notificationRepository repository.NotificationRepository,
+	logger contracts.Logger,
+) *CancelScheduledNotificationUsecase {
+	return &CancelScheduledNotificationUsecase{
+		notificationRepository: notificationRepository,
+		logger:                 logger,
+	}
+}
+
+var ErrNotificationNotScheduled = errors.New("notification is not scheduled")
+
+// CancelScheduledNotification stops a scheduled notification from being sent.
+// Once its time has come and the relay picked it up it can no longer be
+// cancelled.
+func (csu *CancelScheduledNotificationUsecase) CancelScheduledNotification(uuid string) error {
+	record, err := csu.notificationRepository.GetRecordByUUID(uuid)
+	if err != nil {
+		return err
+	}
+
+	if record.Status != value.ScheduledStatus {
+		return ErrNotificationNotScheduled
+	}
+
+	err = csu.notificationRepository.CancelScheduled(uuid)
+	if errors.Is(err, repository.ErrNotScheduled) {
+		return ErrNotificationNotScheduled
+	}
+
+	return err
+}
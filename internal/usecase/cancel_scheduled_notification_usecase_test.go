@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gurodrigues-dev/notifier-app/internal/domain/repository"
+	"github.com/gurodrigues-dev/notifier-app/internal/entity"
+	"github.com/gurodrigues-dev/notifier-app/internal/value"
+	"github.com/gurodrigues-dev/notifier-app/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCancelScheduledNotificationUsecase_CancelScheduledNotification(t *testing.T) {
+	type args struct {
+		uuid string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		setup   func(t *testing.T) *CancelScheduledNotificationUsecase
+		wantErr error
+	}{
+		{
+			name: "there is to return success",
+			args: args{
+				uuid: "5d91f580-030d-4c5d-b3a6-8383f5829bd3",
+			},
+			setup: func(t *testing.T) *CancelScheduledNotificationUsecase {
+				notificationRepository := mocks.NewNotificationRepository(t)
+				logger := mocks.NewLogger(t)
+				notificationRepository.On("GetRecordByUUID", "5d91f580-030d-4c5d-b3a6-8383f5829bd3").Return(&entity.NotificationRecord{
+					UUID:   "5d91f580-030d-4c5d-b3a6-8383f5829bd3",
+					Status: value.ScheduledStatus,
+				}, nil)
+				notificationRepository.On("CancelScheduled", "5d91f580-030d-4c5d-b3a6-8383f5829bd3").Return(nil)
+				return NewCancelScheduledNotificationUsecase(
+					notificationRepository,
+					logger,
+				)
+			},
+			wantErr: nil,
+		},
+		{
+			name: "there is to return not scheduled",
+			args: args{
+				uuid: "5d91f580-030d-4c5d-b3a6-8383f5829bd3",
+			},
+			setup: func(t *testing.T) *CancelScheduledNotificationUsecase {
+				notificationRepository := mocks.NewNotificationRepository(t)
+				logger := mocks.NewLogger(t)
+				notificationRepository.On("GetRecordByUUID", "5d91f580-030d-4c5d-b3a6-8383f5829bd3").Return(&entity.NotificationRecord{
+					UUID:   "5d91f580-030d-4c5d-b3a6-8383f5829bd3",
+					Status: value.QueuedStatus,
+				}, nil)
+				return NewCancelScheduledNotificationUsecase(
+					notificationRepository,
+					logger,
+				)
+			},
+			wantErr: ErrNotificationNotScheduled,
+		},
+		{
+			name: "there is to return claimed by relay",
+			args: args{
+				uuid: "5d91f580-030d-4c5d-b3a6-8383f5829bd3",
+			},
+			setup: func(t *testing.T) *CancelScheduledNotificationUsecase {
+				notificationRepository := mocks.NewNotificationRepository(t)
+				logger := mocks.NewLogger(t)
+				notificationRepository.On("GetRecordByUUID", "5d91f580-030d-4c5d-b3a6-8383f5829bd3").Return(&entity.NotificationRecord{
+					UUID:   "5d91f580-030d-4c5d-b3a6-8383f5829bd3",
+					Status: value.ScheduledStatus,
+				}, nil)
+				notificationRepository.On("CancelScheduled", "5d91f580-030d-4c5d-b3a6-8383f5829bd3").Return(repository.ErrNotScheduled)
+				return NewCancelScheduledNotificationUsecase(
+					notificationRepository,
+					logger,
+				)
+			},
+			wantErr: ErrNotificationNotScheduled,
+		},
+		{
+			name: "there is to return db error",
+			args: args{
+				uuid: "5d91f580-030d-4c5d-b3a6-8383f5829bd3",
+			},
+			setup: func(t *testing.T) *CancelScheduledNotificationUsecase {
+				notificationRepository := mocks.NewNotificationRepository(t)
+				logger := mocks.NewLogger(t)
+				notificationRepository.On("GetRecordByUUID", "5d91f580-030d-4c5d-b3a6-8383f5829bd3").Return(nil, errors.New("db error"))
+				return NewCancelScheduledNotificationUsecase(
+					notificationRepository,
+					logger,
+				)
+			},
+			wantErr: errors.New("db error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usecase := tt.setup(t)
+			err := usecase.CancelScheduledNotification(tt.args.uuid)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
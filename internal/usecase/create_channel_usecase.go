@@ -1,42 +1,72 @@
 package usecase
 
 import (
-	"fmt"
-
 	"github.com/gurodrigues-dev/notifier-app/internal/domain/repository"
 	"github.com/gurodrigues-dev/notifier-app/internal/entity"
 	"github.com/gurodrigues-dev/notifier-app/internal/infra/contracts"
-	"github.com/gurodrigues-dev/notifier-app/internal/value"
-	"github.com/gurodrigues-dev/notifier-app/pkg/slicecommon"
+	"github.com/gurodrigues-dev/notifier-app/internal/infra/provider"
 )
 
 type CreateChannelUsecase struct {
 	channelRepository repository.ChannelRepository
-	ses               contracts.SESIface
+	providers         *provider.Registry
 	logger            contracts.Logger
 }
 
 func NewCreateChannelUsecase(
 	channelRepository repository.ChannelRepository,
-	ses contracts.SESIface,
+	providers *provider.Registry,
 	logger contracts.Logger,
 ) *CreateChannelUsecase {
 	return &CreateChannelUsecase{
 		channelRepository: channelRepository,
-		ses:               ses,
+		providers:         providers,
 		logger:            logger,
 	}
 }
 
+// CreateChannel stores a channel for a known platform. The secret of a
+// channel is only returned here, and only when the platform generated it, so
+// the client can keep it; a secret given by the client is never echoed, and
+// is dropped for platforms that do not take one.
 func (ccu *CreateChannelUsecase) CreateChannel(channel *entity.Channel) (*entity.Channel, error) {
-	if slicecommon.Contains(value.Platforms, channel.Platform) == false {
-		return nil, fmt.Errorf("invalid plataform: %s", channel.Platform)
+	provider, err := ccu.providers.Get(channel.Platform)
+	if err != nil {
+		return nil, err
 	}
-	if channel.Platform == value.EmailPlatform {
-		err := ccu.ses.VerifyEmail(channel.TargetID)
+
+	generator, generates := provider.(contracts.SecretGenerator)
+	validator, validates := provider.(contracts.SecretValidator)
+	switch {
+	case generates:
+		channel.Secret, err = generator.GenerateSecret()
+		if err != nil {
+			return nil, err
+		}
+	case validates:
+		err = validator.ValidateSecret(channel.Secret)
 		if err != nil {
 			return nil, err
 		}
+	default:
+		channel.Secret = ""
+	}
+
+	err = provider.ValidateTarget(channel)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := channel.Secret
+	created, err := ccu.channelRepository.CreateChannel(channel)
+	if err != nil {
+		return nil, err
 	}
-	return ccu.channelRepository.CreateChannel(channel)
+
+	created.Secret = ""
+	if generates {
+		created.Secret = secret
+	}
+
+	return created, nil
 }
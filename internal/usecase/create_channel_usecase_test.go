@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/gurodrigues-dev/notifier-app/internal/entity"
+	"github.com/gurodrigues-dev/notifier-app/internal/infra/provider"
 	"github.com/gurodrigues-dev/notifier-app/internal/value"
 	"github.com/gurodrigues-dev/notifier-app/mocks"
 	"github.com/stretchr/testify/assert"
@@ -26,6 +27,7 @@ func TestCreateChannelUsecase_CreateChannel(t *testing.T) {
 			args: args{
 				channel: &entity.Channel{
 					Platform: value.SlackPlatform,
+					TargetID: "https://hooks.slack.com/services/token",
 				},
 			},
 			setup: func(t *testing.T) *CreateChannelUsecase {
@@ -34,10 +36,11 @@ func TestCreateChannelUsecase_CreateChannel(t *testing.T) {
 				logger := mocks.NewLogger(t)
 				repository.On("CreateChannel", &entity.Channel{
 					Platform: value.SlackPlatform,
+					TargetID: "https://hooks.slack.com/services/token",
 				}).Return(&entity.Channel{}, nil)
 				return NewCreateChannelUsecase(
 					repository,
-					ses,
+					newTestRegistry(ses, mocks.NewWebhook(t)),
 					logger,
 				)
 			},
@@ -60,7 +63,7 @@ func TestCreateChannelUsecase_CreateChannel(t *testing.T) {
 				ses.On("VerifyEmail", mock.Anything).Return(nil)
 				return NewCreateChannelUsecase(
 					repository,
-					ses,
+					newTestRegistry(ses, mocks.NewWebhook(t)),
 					logger,
 				)
 			},
@@ -79,7 +82,27 @@ func TestCreateChannelUsecase_CreateChannel(t *testing.T) {
 				logger := mocks.NewLogger(t)
 				return NewCreateChannelUsecase(
 					repository,
-					ses,
+					newTestRegistry(ses, mocks.NewWebhook(t)),
+					logger,
+				)
+			},
+			wantErr: true,
+		},
+		{
+			name: "there is to return invalid webhook url",
+			args: args{
+				channel: &entity.Channel{
+					Platform: value.DiscordPlatform,
+					TargetID: "not a url",
+				},
+			},
+			setup: func(t *testing.T) *CreateChannelUsecase {
+				repository := mocks.NewChannelRepository(t)
+				ses := mocks.NewSESIface(t)
+				logger := mocks.NewLogger(t)
+				return NewCreateChannelUsecase(
+					repository,
+					newTestRegistry(ses, mocks.NewWebhook(t)),
 					logger,
 				)
 			},
@@ -88,16 +111,16 @@ func TestCreateChannelUsecase_CreateChannel(t *testing.T) {
 		{
 			name: "there is to return db error",
 			args: args{
-				channel: &entity.Channel{Platform: value.SlackPlatform},
+				channel: &entity.Channel{Platform: value.SlackPlatform, TargetID: "https://hooks.slack.com/services/token"},
 			},
 			setup: func(t *testing.T) *CreateChannelUsecase {
 				repository := mocks.NewChannelRepository(t)
 				ses := mocks.NewSESIface(t)
 				logger := mocks.NewLogger(t)
-				repository.On("CreateChannel", &entity.Channel{Platform: value.SlackPlatform}).Return(&entity.Channel{}, errors.New("db error"))
+				repository.On("CreateChannel", &entity.Channel{Platform: value.SlackPlatform, TargetID: "https://hooks.slack.com/services/token"}).Return(&entity.Channel{}, errors.New("db error"))
 				return NewCreateChannelUsecase(
 					repository,
-					ses,
+					newTestRegistry(ses, mocks.NewWebhook(t)),
 					logger,
 				)
 			},
@@ -117,7 +140,7 @@ func TestCreateChannelUsecase_CreateChannel(t *testing.T) {
 				ses.On("VerifyEmail", mock.Anything).Return(errors.New("email error"))
 				return NewCreateChannelUsecase(
 					repository,
-					ses,
+					newTestRegistry(ses, mocks.NewWebhook(t)),
 					logger,
 				)
 			},
@@ -136,3 +159,83 @@ func TestCreateChannelUsecase_CreateChannel(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateChannelUsecase_CreateChannelSecret(t *testing.T) {
+	tests := []struct {
+		name       string
+		channel    *entity.Channel
+		wantSecret bool
+	}{
+		{
+			name:       "there is to return generated webhook secret",
+			channel:    &entity.Channel{Platform: value.WebhookPlatform, TargetID: "https://example.com/hooks", Secret: "chosen"},
+			wantSecret: true,
+		},
+		{
+			name:       "there is to hide telegram bot token",
+			channel:    &entity.Channel{Platform: value.TelegramPlatform, TargetID: "-100123", Secret: "123456:ABC-def_1"},
+			wantSecret: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := mocks.NewChannelRepository(t)
+			webhook := mocks.NewWebhook(t)
+			repository.On("CreateChannel", mock.Anything).Return(func(channel *entity.Channel) *entity.Channel {
+				return channel
+			}, nil)
+
+			usecase := NewCreateChannelUsecase(
+				repository,
+				provider.NewRegistry(
+					provider.NewSignedWebhookProvider(webhook, repository),
+					provider.NewTelegramProvider(webhook, repository, ""),
+				),
+				mocks.NewLogger(t),
+			)
+
+			channel, err := usecase.CreateChannel(tt.channel)
+			assert.NoError(t, err)
+			if tt.wantSecret {
+				assert.Len(t, channel.Secret, 64)
+				assert.NotEqual(t, "chosen", channel.Secret)
+				return
+			}
+			assert.Empty(t, channel.Secret)
+		})
+	}
+}
+
+func TestCreateChannelUsecase_CreateChannelDropsUnusedSecret(t *testing.T) {
+	repository := mocks.NewChannelRepository(t)
+	repository.On("CreateChannel", mock.MatchedBy(func(channel *entity.Channel) bool {
+		return channel.Secret == ""
+	})).Return(&entity.Channel{}, nil)
+
+	usecase := NewCreateChannelUsecase(
+		repository,
+		newTestRegistry(mocks.NewSESIface(t), mocks.NewWebhook(t)),
+		mocks.NewLogger(t),
+	)
+
+	_, err := usecase.CreateChannel(&entity.Channel{
+		Platform: value.SlackPlatform,
+		TargetID: "https://hooks.slack.com/services/token",
+		Secret:   "unused",
+	})
+	assert.NoError(t, err)
+}
+
+func TestCreateChannelUsecase_CreateChannelInvalidTelegramToken(t *testing.T) {
+	repository := mocks.NewChannelRepository(t)
+	webhook := mocks.NewWebhook(t)
+
+	usecase := NewCreateChannelUsecase(
+		repository,
+		provider.NewRegistry(provider.NewTelegramProvider(webhook, repository, "")),
+		mocks.NewLogger(t),
+	)
+
+	_, err := usecase.CreateChannel(&entity.Channel{Platform: value.TelegramPlatform, TargetID: "-100123", Secret: "not a token"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,205 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gurodrigues-dev/notifier-app/internal/domain/repository"
+	"github.com/gurodrigues-dev/notifier-app/internal/entity"
+	"github.com/gurodrigues-dev/notifier-app/internal/infra/contracts"
+	"github.com/gurodrigues-dev/notifier-app/internal/value"
+	"github.com/gurodrigues-dev/notifier-app/pkg/slicecommon"
+	"github.com/gurodrigues-dev/notifier-app/pkg/stringcommon"
+)
+
+// CreateNotificationBatchUsecase accepts many notifications at once, sharing
+// the channel lookup and the database transaction between them.
+type CreateNotificationBatchUsecase struct {
+	*CreateNotificationUsecase
+}
+
+func NewCreateNotificationBatchUsecase(
+	notificationRepository repository.NotificationRepository,
+	channelRepository repository.ChannelRepository,
+	cacher contracts.Cacher,
+	logger contracts.Logger,
+) *CreateNotificationBatchUsecase {
+	return &CreateNotificationBatchUsecase{
+		CreateNotificationUsecase: NewCreateNotificationUsecase(
+			notificationRepository,
+			channelRepository,
+			cacher,
+			logger,
+		),
+	}
+}
+
+var ErrBatchTooLarge = errors.New("too many notifications in batch")
+
+// CreateNotificationBatch validates, deduplicates and records every input,
+// returning one result per input in the same order. Invalid or duplicate
+// inputs do not fail the batch; an error is returned only when nothing could
+// be recorded.
+func (cnbu *CreateNotificationBatchUsecase) CreateNotificationBatch(inputs []value.NotificationInput) (*value.NotificationBatchOutput, error) {
+	limit := value.GetNotificationBatchMax()
+	if len(inputs) > limit {
+		return nil, fmt.Errorf("%w: %d, the limit is %d", ErrBatchTooLarge, len(inputs), limit)
+	}
+
+	results := make([]value.NotificationBatchResult, len(inputs))
+	validate := validator.New()
+
+	var (
+		valid []int
+		refs  []string
+		seen  = make(map[string]bool)
+	)
+	for i, input := range inputs {
+		results[i] = value.NotificationBatchResult{Index: i, UUID: input.UUID}
+
+		err := validate.Struct(input)
+		if err == nil {
+			err = validateExpiry(input)
+		}
+		if err != nil {
+			results[i].Status = value.BatchInvalid
+			results[i].Error = err.Error()
+			continue
+		}
+
+		if seen[input.UUID] {
+			results[i].Status = value.BatchDuplicate
+			continue
+		}
+		seen[input.UUID] = true
+		valid = append(valid, i)
+
+		for _, ref := range input.Channels {
+			if !slicecommon.Contains(refs, ref) {
+				refs = append(refs, ref)
+			}
+		}
+	}
+
+	if len(valid) == 0 {
+		return summarizeBatch(results), nil
+	}
+
+	cnbu.logger.Infof("getting channels")
+	channels, err := cnbu.GetChannels(value.NotificationInput{Channels: refs})
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		pending  []int
+		claimed  []string
+		records  []*entity.NotificationRecord
+		messages []*entity.OutboxMessage
+	)
+	for _, i := range valid {
+		notification := cnbu.buildNotificationMessage(inputs[i])
+		notification.Channels = resolveChannels(inputs[i].Channels, channels)
+
+		serializedMessage, err := stringcommon.SerializeToJSON(notification)
+		if err != nil {
+			cnbu.releaseAll(claimed)
+			return nil, err
+		}
+
+		ok, cacheErr := cnbu.claim(notification.UUID, *notification)
+		if cacheErr != nil {
+			cnbu.logger.Errorf(fmt.Sprintf("error claiming notification %s, relying on database: %v", notification.UUID, cacheErr))
+		} else if !ok {
+			results[i].Status = value.BatchDuplicate
+			continue
+		} else {
+			claimed = append(claimed, notification.UUID)
+		}
+
+		record, outbox, err := newNotificationOutbox(notification, serializedMessage, inputs[i].SendAt)
+		if err != nil {
+			cnbu.releaseAll(claimed)
+			return nil, err
+		}
+
+		pending = append(pending, i)
+		results[i].Channels = sortedChannelIDs(notification.Channels)
+		records = append(records, record)
+		messages = append(messages, outbox)
+	}
+
+	if len(records) == 0 {
+		return summarizeBatch(results), nil
+	}
+
+	cnbu.logger.Infof("recording notifications")
+	duplicates, err := cnbu.notificationRepository.CreateRecordsWithOutbox(records, messages)
+	if err != nil {
+		cnbu.releaseAll(claimed)
+		return nil, err
+	}
+
+	for j, i := range pending {
+		if slicecommon.Contains(duplicates, records[j].UUID) {
+			results[i].Status = value.BatchDuplicate
+			results[i].Channels = nil
+			continue
+		}
+
+		acceptedAt := records[j].CreatedAt
+		results[i].Status = value.BatchAccepted
+		results[i].AcceptedAt = &acceptedAt
+		if records[j].Status == value.ScheduledStatus {
+			results[i].SendAt = &messages[j].AvailableAt
+		}
+	}
+
+	return summarizeBatch(results), nil
+}
+
+func (cnbu *CreateNotificationBatchUsecase) releaseAll(uuids []string) {
+	for _, uuid := range uuids {
+		cnbu.release(uuid)
+	}
+}
+
+// resolveChannels picks from channels the ones refs point to, either by ID
+// or by group.
+func resolveChannels(refs []string, channels map[int]entity.Channel) map[int]entity.Channel {
+	ids, groups := slicecommon.Partition(refs)
+
+	targets := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		target, err := strconv.Atoi(id)
+		if err == nil {
+			targets[target] = true
+		}
+	}
+
+	resolved := make(map[int]entity.Channel)
+	for id, channel := range channels {
+		if targets[id] || slicecommon.Contains(groups, channel.Group) {
+			resolved[id] = channel
+		}
+	}
+
+	return resolved
+}
+
+func summarizeBatch(results []value.NotificationBatchResult) *value.NotificationBatchOutput {
+	output := &value.NotificationBatchOutput{Results: results}
+	for _, result := range results {
+		switch result.Status {
+		case value.BatchAccepted:
+			output.Accepted++
+		case value.BatchDuplicate:
+			output.Duplicates++
+		case value.BatchInvalid:
+			output.Invalid++
+		}
+	}
+	return output
+}
@@ -0,0 +1,206 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gurodrigues-dev/notifier-app/internal/entity"
+	"github.com/gurodrigues-dev/notifier-app/internal/value"
+	"github.com/gurodrigues-dev/notifier-app/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func batchInput(uuid string, channels ...string) value.NotificationInput {
+	return value.NotificationInput{
+		Channels: channels,
+		UUID:     uuid,
+		Title:    "Payment Success",
+		Event: value.Event{
+			Name:      "payment_success",
+			Timestamp: 1748355999,
+			Requester: "requester",
+			Receiver:  "receiver",
+			Currency:  "BRL",
+			Category:  "pix",
+			CostCents: 9000,
+		},
+	}
+}
+
+func TestCreateNotificationBatchUsecase_CreateNotificationBatch(t *testing.T) {
+	type args struct {
+		inputs []value.NotificationInput
+	}
+	tests := []struct {
+		name         string
+		args         args
+		setup        func(t *testing.T) *CreateNotificationBatchUsecase
+		wantStatuses []string
+		wantChannels [][]int
+		wantErr      bool
+	}{
+		{
+			name: "there is to return success",
+			args: args{
+				inputs: []value.NotificationInput{
+					batchInput("1", "marketing"),
+					batchInput("2", "2"),
+					batchInput("3", "2", "marketing"),
+				},
+			},
+			setup: func(t *testing.T) *CreateNotificationBatchUsecase {
+				notificationRepository := mocks.NewNotificationRepository(t)
+				channelRepository := mocks.NewChannelRepository(t)
+				cacher := mocks.NewCacher(t)
+				logger := mocks.NewLogger(t)
+
+				logger.On("Infof", mock.Anything, mock.Anything).Return()
+				channelRepository.On("GetByIDs", []string{"2"}).Return([]entity.Channel{
+					{ID: 2, Platform: value.EmailPlatform, Group: "admin"},
+				}, nil).Once()
+				channelRepository.On("GetByGroups", []string{"marketing"}).Return([]entity.Channel{
+					{ID: 4, Platform: value.SlackPlatform, Group: "marketing"},
+					{ID: 5, Platform: value.DiscordPlatform, Group: "marketing"},
+				}, nil).Once()
+				cacher.On("SetNX", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+				notificationRepository.On("CreateRecordsWithOutbox", mock.MatchedBy(func(records []*entity.NotificationRecord) bool {
+					return len(records) == 3
+				}), mock.MatchedBy(func(messages []*entity.OutboxMessage) bool {
+					return len(messages) == 3 && messages[0].Key == "1" && messages[2].Topic == value.GetTopic()
+				})).Return(nil, nil)
+
+				return NewCreateNotificationBatchUsecase(
+					notificationRepository,
+					channelRepository,
+					cacher,
+					logger,
+				)
+			},
+			wantStatuses: []string{value.BatchAccepted, value.BatchAccepted, value.BatchAccepted},
+			wantChannels: [][]int{{4, 5}, {2}, {2, 4, 5}},
+			wantErr:      false,
+		},
+		{
+			name: "there is to return invalid and duplicate items",
+			args: args{
+				inputs: []value.NotificationInput{
+					batchInput("1", "2"),
+					batchInput("", "2"),
+					batchInput("1", "2"),
+					batchInput("2", "2"),
+					batchInput("3", "2"),
+				},
+			},
+			setup: func(t *testing.T) *CreateNotificationBatchUsecase {
+				notificationRepository := mocks.NewNotificationRepository(t)
+				channelRepository := mocks.NewChannelRepository(t)
+				cacher := mocks.NewCacher(t)
+				logger := mocks.NewLogger(t)
+
+				logger.On("Infof", mock.Anything, mock.Anything).Return()
+				channelRepository.On("GetByIDs", []string{"2"}).Return([]entity.Channel{
+					{ID: 2, Platform: value.EmailPlatform, Group: "admin"},
+				}, nil).Once()
+				cacher.On("SetNX", "1", mock.Anything, mock.Anything).Return(true, nil)
+				cacher.On("SetNX", "2", mock.Anything, mock.Anything).Return(false, nil)
+				cacher.On("SetNX", "3", mock.Anything, mock.Anything).Return(true, nil)
+				notificationRepository.On("CreateRecordsWithOutbox", mock.MatchedBy(func(records []*entity.NotificationRecord) bool {
+					return len(records) == 2 && records[0].UUID == "1" && records[1].UUID == "3"
+				}), mock.Anything).Return([]string{"3"}, nil)
+
+				return NewCreateNotificationBatchUsecase(
+					notificationRepository,
+					channelRepository,
+					cacher,
+					logger,
+				)
+			},
+			wantStatuses: []string{value.BatchAccepted, value.BatchInvalid, value.BatchDuplicate, value.BatchDuplicate, value.BatchDuplicate},
+			wantChannels: [][]int{{2}, nil, nil, nil, nil},
+			wantErr:      false,
+		},
+		{
+			name: "there is to return only invalid items",
+			args: args{
+				inputs: []value.NotificationInput{
+					batchInput("1"),
+				},
+			},
+			setup: func(t *testing.T) *CreateNotificationBatchUsecase {
+				return NewCreateNotificationBatchUsecase(
+					mocks.NewNotificationRepository(t),
+					mocks.NewChannelRepository(t),
+					mocks.NewCacher(t),
+					mocks.NewLogger(t),
+				)
+			},
+			wantStatuses: []string{value.BatchInvalid},
+			wantChannels: [][]int{nil},
+			wantErr:      false,
+		},
+		{
+			name: "there is to return batch too large",
+			args: args{
+				inputs: make([]value.NotificationInput, value.GetNotificationBatchMax()+1),
+			},
+			setup: func(t *testing.T) *CreateNotificationBatchUsecase {
+				return NewCreateNotificationBatchUsecase(
+					mocks.NewNotificationRepository(t),
+					mocks.NewChannelRepository(t),
+					mocks.NewCacher(t),
+					mocks.NewLogger(t),
+				)
+			},
+			wantErr: true,
+		},
+		{
+			name: "there is to return db error releasing claims",
+			args: args{
+				inputs: []value.NotificationInput{
+					batchInput("1", "2"),
+					batchInput("2", "2"),
+				},
+			},
+			setup: func(t *testing.T) *CreateNotificationBatchUsecase {
+				notificationRepository := mocks.NewNotificationRepository(t)
+				channelRepository := mocks.NewChannelRepository(t)
+				cacher := mocks.NewCacher(t)
+				logger := mocks.NewLogger(t)
+
+				logger.On("Infof", mock.Anything, mock.Anything).Return()
+				channelRepository.On("GetByIDs", []string{"2"}).Return([]entity.Channel{}, nil)
+				cacher.On("SetNX", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+				notificationRepository.On("CreateRecordsWithOutbox", mock.Anything, mock.Anything).Return(nil, errors.New("db error"))
+				cacher.On("Delete", "1").Return(nil).Once()
+				cacher.On("Delete", "2").Return(nil).Once()
+
+				return NewCreateNotificationBatchUsecase(
+					notificationRepository,
+					channelRepository,
+					cacher,
+					logger,
+				)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usecase := tt.setup(t)
+			output, err := usecase.CreateNotificationBatch(tt.args.inputs)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, output.Results, len(tt.wantStatuses))
+			for i, result := range output.Results {
+				assert.Equal(t, i, result.Index)
+				assert.Equal(t, tt.wantStatuses[i], result.Status)
+				assert.Equal(t, tt.wantChannels[i], result.Channels)
+				assert.Equal(t, result.Status == value.BatchAccepted, result.AcceptedAt != nil)
+			}
+		})
+	}
+}
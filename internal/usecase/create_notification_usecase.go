@@ -1,7 +1,9 @@
 package usecase
 
 import (
+	"errors"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -11,14 +13,12 @@ import (
 	"github.com/gurodrigues-dev/notifier-app/internal/value"
 	"github.com/gurodrigues-dev/notifier-app/pkg/slicecommon"
 	"github.com/gurodrigues-dev/notifier-app/pkg/stringcommon"
-	"github.com/redis/go-redis/v9"
 )
 
 type CreateNotificationUsecase struct {
 	notificationRepository repository.NotificationRepository
 	channelRepository      repository.ChannelRepository
 	cacher                 contracts.Cacher
-	queue                  contracts.Queue
 	logger                 contracts.Logger
 }
 
@@ -26,14 +26,12 @@ func NewCreateNotificationUsecase(
 	notificationRepository repository.NotificationRepository,
 	channelRepository repository.ChannelRepository,
 	cacher contracts.Cacher,
-	queue contracts.Queue,
 	logger contracts.Logger,
 ) *CreateNotificationUsecase {
 	return &CreateNotificationUsecase{
 		notificationRepository: notificationRepository,
 		channelRepository:      channelRepository,
 		cacher:                 cacher,
-		queue:                  queue,
 		logger:                 logger,
 	}
 }
@@ -42,11 +40,21 @@ var (
 	timeDuration = 2 * time.Hour
 )
 
-func (cnu *CreateNotificationUsecase) CreateNotification(input value.NotificationInput) (err error) {
+var (
+	ErrNotificationAlreadyExists = errors.New("notification already exists")
+	ErrInvalidExpiry             = errors.New("expires_at must be in the future and after send_at")
+)
+
+func (cnu *CreateNotificationUsecase) CreateNotification(input value.NotificationInput) (accepted *value.NotificationAccepted, err error) {
+	err = validateExpiry(input)
+	if err != nil {
+		return nil, err
+	}
+
 	cnu.logger.Infof("getting channels")
 	channels, err := cnu.GetChannels(input)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	cnu.logger.Infof("build notification")
@@ -56,10 +64,10 @@ func (cnu *CreateNotificationUsecase) CreateNotification(input value.Notificatio
 	cnu.logger.Infof("serializing notification")
 	serializedMessage, err := stringcommon.SerializeToJSON(notification)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer func() {
-		if err != nil {
+		if err != nil && !errors.Is(err, ErrNotificationAlreadyExists) {
 			dbError := cnu.notificationRepository.CreateNotification(&entity.NotificationError{
 				UUID:  notification.UUID,
 				Body:  serializedMessage,
@@ -72,29 +80,46 @@ func (cnu *CreateNotificationUsecase) CreateNotification(input value.Notificatio
 		}
 	}()
 
-	cnu.logger.Infof("validating cache")
-	hasCache, err := cnu.isCacheValid(notification.UUID)
-	if err != nil && err != redis.Nil {
-		return err
+	// Redis is only the fast path for duplicates: when it is unavailable the
+	// unique UUID in Postgres still rejects them, just later.
+	cnu.logger.Infof("claiming notification")
+	claimed, cacheErr := cnu.claim(notification.UUID, *notification)
+	if cacheErr != nil {
+		cnu.logger.Errorf(fmt.Sprintf("error claiming notification %s, relying on database: %v", notification.UUID, cacheErr))
+	} else if !claimed {
+		return nil, ErrNotificationAlreadyExists
 	}
 
-	if hasCache {
-		return fmt.Errorf("notification already exists in cache")
+	record, outbox, err := newNotificationOutbox(notification, serializedMessage, input.SendAt)
+	if err != nil {
+		if claimed {
+			cnu.release(notification.UUID)
+		}
+		return nil, err
 	}
 
-	cnu.logger.Infof("creating cache")
-	err = cnu.setCache(notification.UUID, *notification)
+	cnu.logger.Infof("recording notification")
+	err = cnu.notificationRepository.CreateRecordWithOutbox(record, outbox)
+	if errors.Is(err, repository.ErrDuplicateKey) {
+		return nil, ErrNotificationAlreadyExists
+	}
 	if err != nil {
-		return err
+		if claimed {
+			cnu.release(notification.UUID)
+		}
+		return nil, err
 	}
 
-	cnu.logger.Infof("sending notification")
-	err = cnu.queue.Produce(value.GetTopic(), string(serializedMessage))
-	if err != nil {
-		return err
+	accepted = &value.NotificationAccepted{
+		UUID:       notification.UUID,
+		Channels:   sortedChannelIDs(channels),
+		AcceptedAt: record.CreatedAt,
+	}
+	if record.Status == value.ScheduledStatus {
+		accepted.SendAt = &outbox.AvailableAt
 	}
 
-	return nil
+	return accepted, nil
 }
 
 func (cnu *CreateNotificationUsecase) GetChannels(input value.NotificationInput) (map[int]entity.Channel, error) {
@@ -183,24 +208,51 @@ func (cnu *CreateNotificationUsecase) buildNotificationMessage(input value.Notif
 			Timestamp: input.Event.Timestamp,
 			CostCents: input.Event.CostCents,
 		},
+		ExpiresAt: input.ExpiresAt,
 	}
 
 	return notification
 }
 
-func (cnu *CreateNotificationUsecase) isCacheValid(uuid string) (bool, error) {
-	value, err := cnu.cacher.Get(uuid)
-	if err != nil {
-		return false, err
+// validateExpiry rejects a notification that would expire before it could be
+// sent, either now or at its scheduled time.
+func validateExpiry(input value.NotificationInput) error {
+	if input.ExpiresAt == nil {
+		return nil
 	}
-	return value != "", nil
-}
 
-func (cnu *CreateNotificationUsecase) setCache(uuid string, value entity.Notification) error {
-	err := cnu.cacher.Set(uuid, value, timeDuration)
-	if err != nil {
-		return err
+	sendAt := time.Now()
+	if input.SendAt != nil && input.SendAt.After(sendAt) {
+		sendAt = *input.SendAt
+	}
+
+	if !input.ExpiresAt.After(sendAt) {
+		return ErrInvalidExpiry
 	}
 
 	return nil
 }
+
+func sortedChannelIDs(channels map[int]entity.Channel) []int {
+	ids := make([]int, 0, len(channels))
+	for id := range channels {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// claim atomically marks the UUID as taken and reports whether this request
+// got it, so concurrent submissions of the same notification are accepted once.
+func (cnu *CreateNotificationUsecase) claim(uuid string, value entity.Notification) (bool, error) {
+	return cnu.cacher.SetNX(uuid, value, timeDuration)
+}
+
+// release frees a claimed UUID whose notification could not be accepted, so
+// the client can retry it.
+func (cnu *CreateNotificationUsecase) release(uuid string) {
+	err := cnu.cacher.Delete(uuid)
+	if err != nil {
+		cnu.logger.Errorf(fmt.Sprintf("error releasing notification %s: %v", uuid, err))
+	}
+}
@@ -3,7 +3,9 @@ package usecase
 import (
 	"errors"
 	"testing"
+	"time"
 
+	"github.com/gurodrigues-dev/notifier-app/internal/domain/repository"
 	"github.com/gurodrigues-dev/notifier-app/internal/entity"
 	"github.com/gurodrigues-dev/notifier-app/internal/value"
 	"github.com/gurodrigues-dev/notifier-app/mocks"
@@ -12,6 +14,9 @@ import (
 )
 
 func TestCreateNotificationUsecase_CreateNotification(t *testing.T) {
+	sendAt := time.Now().Add(time.Hour)
+	expiresAt := time.Now().Add(time.Minute)
+
 	type args struct {
 		input value.NotificationInput
 	}
@@ -58,7 +63,6 @@ func TestCreateNotificationUsecase_CreateNotification(t *testing.T) {
 				notificationRepository := mocks.NewNotificationRepository(t)
 				channelRepository := mocks.NewChannelRepository(t)
 				cacher := mocks.NewCacher(t)
-				kafka := mocks.NewQueue(t)
 				logger := mocks.NewLogger(t)
 
 				logger.On("Infof", mock.Anything, mock.Anything).Return()
@@ -67,22 +71,94 @@ func TestCreateNotificationUsecase_CreateNotification(t *testing.T) {
 				logger.On("Infof", mock.Anything, mock.Anything).Return()
 				logger.On("Infof", mock.Anything, mock.Anything).Return()
 				logger.On("Infof", mock.Anything, mock.Anything).Return()
-				cacher.On("Get", input.UUID).Return("", nil)
+				cacher.On("SetNX", input.UUID, mock.Anything, mock.Anything).Return(true, nil)
 				logger.On("Infof", mock.Anything, mock.Anything).Return()
-				cacher.On("Set", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+				notificationRepository.On("CreateRecordWithOutbox", mock.Anything, mock.MatchedBy(func(message *entity.OutboxMessage) bool {
+					return message.Topic == value.GetTopic() && message.Key == input.UUID
+				})).Return(nil)
+
+				return NewCreateNotificationUsecase(
+					notificationRepository,
+					channelRepository,
+					cacher,
+					logger,
+				)
+			},
+			wantErr: false,
+		},
+		{
+			name: "there is to return success scheduled",
+			args: args{
+				input: value.NotificationInput{
+					Channels: []string{"2"},
+					UUID:     "2bbcdd20-1ea6-42be-8484-02f3007e3463",
+					Title:    "Payment Success",
+					Event: value.Event{
+						Name:      "payment_success",
+						Timestamp: 1748355999,
+						Requester: "requester",
+						Receiver:  "receiver",
+						Currency:  "BRL",
+						Category:  "pix",
+						CostCents: 9000,
+					},
+					SendAt: &sendAt,
+				},
+			},
+			setup: func(t *testing.T) *CreateNotificationUsecase {
+				notificationRepository := mocks.NewNotificationRepository(t)
+				channelRepository := mocks.NewChannelRepository(t)
+				cacher := mocks.NewCacher(t)
+				logger := mocks.NewLogger(t)
+
 				logger.On("Infof", mock.Anything, mock.Anything).Return()
-				kafka.On("Produce", mock.Anything, mock.Anything).Return(nil)
+				channelRepository.On("GetByIDs", []string{"2"}).Return([]entity.Channel{}, nil)
+				cacher.On("SetNX", "2bbcdd20-1ea6-42be-8484-02f3007e3463", mock.Anything, mock.Anything).Return(true, nil)
+				notificationRepository.On("CreateRecordWithOutbox", mock.MatchedBy(func(record *entity.NotificationRecord) bool {
+					return record.Status == value.ScheduledStatus
+				}), mock.MatchedBy(func(message *entity.OutboxMessage) bool {
+					return message.AvailableAt.Equal(sendAt)
+				})).Return(nil)
 
 				return NewCreateNotificationUsecase(
 					notificationRepository,
 					channelRepository,
 					cacher,
-					kafka,
 					logger,
 				)
 			},
 			wantErr: false,
 		},
+		{
+			name: "there is to return expiry before send at",
+			args: args{
+				input: value.NotificationInput{
+					Channels: []string{"2"},
+					UUID:     "2bbcdd20-1ea6-42be-8484-02f3007e3463",
+					Title:    "Payment Success",
+					Event: value.Event{
+						Name:      "payment_success",
+						Timestamp: 1748355999,
+						Requester: "requester",
+						Receiver:  "receiver",
+						Currency:  "BRL",
+						Category:  "pix",
+						CostCents: 9000,
+					},
+					SendAt:    &sendAt,
+					ExpiresAt: &expiresAt,
+				},
+			},
+			setup: func(t *testing.T) *CreateNotificationUsecase {
+				return NewCreateNotificationUsecase(
+					mocks.NewNotificationRepository(t),
+					mocks.NewChannelRepository(t),
+					mocks.NewCacher(t),
+					mocks.NewLogger(t),
+				)
+			},
+			wantErr: true,
+		},
 		{
 			name: "there is to return fail to get channels by ids",
 			args: args{
@@ -105,7 +181,6 @@ func TestCreateNotificationUsecase_CreateNotification(t *testing.T) {
 				notificationRepository := mocks.NewNotificationRepository(t)
 				channelRepository := mocks.NewChannelRepository(t)
 				cacher := mocks.NewCacher(t)
-				kafka := mocks.NewQueue(t)
 				logger := mocks.NewLogger(t)
 
 				logger.On("Infof", mock.Anything, mock.Anything).Return()
@@ -118,14 +193,13 @@ func TestCreateNotificationUsecase_CreateNotification(t *testing.T) {
 					notificationRepository,
 					channelRepository,
 					cacher,
-					kafka,
 					logger,
 				)
 			},
 			wantErr: true,
 		},
 		{
-			name: "there is to return has cache",
+			name: "there is to return already exists",
 			args: args{
 				input: value.NotificationInput{
 					Channels: []string{"marketing", "2"},
@@ -159,7 +233,6 @@ func TestCreateNotificationUsecase_CreateNotification(t *testing.T) {
 				notificationRepository := mocks.NewNotificationRepository(t)
 				channelRepository := mocks.NewChannelRepository(t)
 				cacher := mocks.NewCacher(t)
-				kafka := mocks.NewQueue(t)
 				logger := mocks.NewLogger(t)
 
 				logger.On("Infof", mock.Anything, mock.Anything).Return()
@@ -168,22 +241,19 @@ func TestCreateNotificationUsecase_CreateNotification(t *testing.T) {
 				logger.On("Infof", mock.Anything, mock.Anything).Return()
 				logger.On("Infof", mock.Anything, mock.Anything).Return()
 				logger.On("Infof", mock.Anything, mock.Anything).Return()
-				cacher.On("Get", input.UUID).Return("cache", nil)
-
-				notificationRepository.On("CreateNotification", mock.Anything).Maybe().Return(nil)
+				cacher.On("SetNX", input.UUID, mock.Anything, mock.Anything).Return(false, nil)
 
 				return NewCreateNotificationUsecase(
 					notificationRepository,
 					channelRepository,
 					cacher,
-					kafka,
 					logger,
 				)
 			},
 			wantErr: true,
 		},
 		{
-			name: "there is to return error cache",
+			name: "there is to return success when cache is unavailable",
 			args: args{
 				input: value.NotificationInput{
 					Channels: []string{"marketing", "2"},
@@ -217,7 +287,6 @@ func TestCreateNotificationUsecase_CreateNotification(t *testing.T) {
 				notificationRepository := mocks.NewNotificationRepository(t)
 				channelRepository := mocks.NewChannelRepository(t)
 				cacher := mocks.NewCacher(t)
-				kafka := mocks.NewQueue(t)
 				logger := mocks.NewLogger(t)
 
 				logger.On("Infof", mock.Anything, mock.Anything).Return()
@@ -226,7 +295,68 @@ func TestCreateNotificationUsecase_CreateNotification(t *testing.T) {
 				logger.On("Infof", mock.Anything, mock.Anything).Return()
 				logger.On("Infof", mock.Anything, mock.Anything).Return()
 				logger.On("Infof", mock.Anything, mock.Anything).Return()
-				cacher.On("Get", input.UUID).Return("", errors.New("get cache error"))
+				cacher.On("SetNX", input.UUID, mock.Anything, mock.Anything).Return(false, errors.New("set cache error"))
+				logger.On("Errorf", mock.Anything).Return()
+				logger.On("Infof", mock.Anything, mock.Anything).Return()
+				notificationRepository.On("CreateRecordWithOutbox", mock.Anything, mock.Anything).Return(nil)
+
+				return NewCreateNotificationUsecase(
+					notificationRepository,
+					channelRepository,
+					cacher,
+					logger,
+				)
+			},
+			wantErr: false,
+		},
+		{
+			name: "there is to return error when release claim fails",
+			args: args{
+				input: value.NotificationInput{
+					Channels: []string{"marketing", "2"},
+					UUID:     "2bbcdd20-1ea6-42be-8484-02f3007e3463",
+					Title:    "Payment Success",
+					Event: value.Event{
+						Name:      "payment_success",
+						Timestamp: 1748355999,
+						Requester: "requester",
+						Receiver:  "receiver",
+						Currency:  "BRL",
+						Category:  "pix",
+						CostCents: 9000,
+					},
+				},
+			},
+			setup: func(t *testing.T) *CreateNotificationUsecase {
+				input := value.NotificationInput{
+					Channels: []string{"marketing", "2"},
+					UUID:     "2bbcdd20-1ea6-42be-8484-02f3007e3463",
+					Event: value.Event{
+						Name:      "payment_success",
+						Timestamp: 1748355999,
+						Requester: "requester",
+						Receiver:  "receiver",
+						Currency:  "BRL",
+						Category:  "pix",
+						CostCents: 9000,
+					},
+				}
+				notificationRepository := mocks.NewNotificationRepository(t)
+				channelRepository := mocks.NewChannelRepository(t)
+				cacher := mocks.NewCacher(t)
+				logger := mocks.NewLogger(t)
+
+				logger.On("Infof", mock.Anything, mock.Anything).Return()
+				channelRepository.On("GetByIDs", []string{"2"}).Return([]entity.Channel{}, nil)
+				channelRepository.On("GetByGroups", []string{"marketing"}).Return([]entity.Channel{}, nil)
+				logger.On("Infof", mock.Anything, mock.Anything).Return()
+				logger.On("Infof", mock.Anything, mock.Anything).Return()
+				logger.On("Infof", mock.Anything, mock.Anything).Return()
+				cacher.On("SetNX", input.UUID, mock.Anything, mock.Anything).Return(true, nil)
+				logger.On("Infof", mock.Anything, mock.Anything).Return()
+				notificationRepository.On("CreateRecordWithOutbox", mock.Anything, mock.Anything).Return(errors.New("db error"))
+				cacher.On("Delete", input.UUID).Return(errors.New("delete cache error"))
+				logger.On("Errorf", mock.Anything).Return()
 
 				notificationRepository.On("CreateNotification", mock.Anything).Maybe().Return(nil)
 
@@ -234,14 +364,13 @@ func TestCreateNotificationUsecase_CreateNotification(t *testing.T) {
 					notificationRepository,
 					channelRepository,
 					cacher,
-					kafka,
 					logger,
 				)
 			},
 			wantErr: true,
 		},
 		{
-			name: "there is to return error to set cache",
+			name: "there is to return error to record notification in outbox",
 			args: args{
 				input: value.NotificationInput{
 					Channels: []string{"marketing", "2"},
@@ -275,7 +404,6 @@ func TestCreateNotificationUsecase_CreateNotification(t *testing.T) {
 				notificationRepository := mocks.NewNotificationRepository(t)
 				channelRepository := mocks.NewChannelRepository(t)
 				cacher := mocks.NewCacher(t)
-				kafka := mocks.NewQueue(t)
 				logger := mocks.NewLogger(t)
 
 				logger.On("Infof", mock.Anything, mock.Anything).Return()
@@ -284,9 +412,10 @@ func TestCreateNotificationUsecase_CreateNotification(t *testing.T) {
 				logger.On("Infof", mock.Anything, mock.Anything).Return()
 				logger.On("Infof", mock.Anything, mock.Anything).Return()
 				logger.On("Infof", mock.Anything, mock.Anything).Return()
-				cacher.On("Get", input.UUID).Return("", nil)
+				cacher.On("SetNX", input.UUID, mock.Anything, mock.Anything).Return(true, nil)
 				logger.On("Infof", mock.Anything, mock.Anything).Return()
-				cacher.On("Set", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("set cache error"))
+				notificationRepository.On("CreateRecordWithOutbox", mock.Anything, mock.Anything).Return(errors.New("db error"))
+				cacher.On("Delete", input.UUID).Return(nil)
 
 				notificationRepository.On("CreateNotification", mock.Anything).Maybe().Return(nil)
 
@@ -294,14 +423,13 @@ func TestCreateNotificationUsecase_CreateNotification(t *testing.T) {
 					notificationRepository,
 					channelRepository,
 					cacher,
-					kafka,
 					logger,
 				)
 			},
 			wantErr: true,
 		},
 		{
-			name: "there is to return error to produce message to kafka",
+			name: "there is to return already exists in database",
 			args: args{
 				input: value.NotificationInput{
 					Channels: []string{"marketing", "2"},
@@ -335,7 +463,6 @@ func TestCreateNotificationUsecase_CreateNotification(t *testing.T) {
 				notificationRepository := mocks.NewNotificationRepository(t)
 				channelRepository := mocks.NewChannelRepository(t)
 				cacher := mocks.NewCacher(t)
-				kafka := mocks.NewQueue(t)
 				logger := mocks.NewLogger(t)
 
 				logger.On("Infof", mock.Anything, mock.Anything).Return()
@@ -344,19 +471,14 @@ func TestCreateNotificationUsecase_CreateNotification(t *testing.T) {
 				logger.On("Infof", mock.Anything, mock.Anything).Return()
 				logger.On("Infof", mock.Anything, mock.Anything).Return()
 				logger.On("Infof", mock.Anything, mock.Anything).Return()
-				cacher.On("Get", input.UUID).Return("", nil)
+				cacher.On("SetNX", input.UUID, mock.Anything, mock.Anything).Return(true, nil)
 				logger.On("Infof", mock.Anything, mock.Anything).Return()
-				cacher.On("Set", mock.Anything, mock.Anything, mock.Anything).Return(nil)
-				logger.On("Infof", mock.Anything, mock.Anything).Return()
-				kafka.On("Produce", mock.Anything, mock.Anything).Return(errors.New("kafka produce error"))
-
-				notificationRepository.On("CreateNotification", mock.Anything).Maybe().Return(nil)
+				notificationRepository.On("CreateRecordWithOutbox", mock.Anything, mock.Anything).Return(repository.ErrDuplicateKey)
 
 				return NewCreateNotificationUsecase(
 					notificationRepository,
 					channelRepository,
 					cacher,
-					kafka,
 					logger,
 				)
 			},
@@ -366,12 +488,14 @@ func TestCreateNotificationUsecase_CreateNotification(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			usecase := tt.setup(t)
-			err := usecase.CreateNotification(tt.args.input)
+			accepted, err := usecase.CreateNotification(tt.args.input)
 			if tt.wantErr {
 				assert.Error(t, err)
 				return
 			}
 			assert.NoError(t, err)
+			assert.Equal(t, tt.args.input.UUID, accepted.UUID)
+			assert.Equal(t, tt.args.input.SendAt, accepted.SendAt)
 		})
 	}
 }
@@ -1,127 +1,402 @@
 package usecase
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/gurodrigues-dev/notifier-app/internal/domain/repository"
 	"github.com/gurodrigues-dev/notifier-app/internal/entity"
 	"github.com/gurodrigues-dev/notifier-app/internal/infra/contracts"
+	"github.com/gurodrigues-dev/notifier-app/internal/infra/provider"
 	"github.com/gurodrigues-dev/notifier-app/internal/value"
 	"github.com/gurodrigues-dev/notifier-app/pkg/stringcommon"
 )
 
 type DispatcherUsecase struct {
 	notificationRepository repository.NotificationRepository
-	ses                    contracts.SESIface
-	webhook                contracts.Webhook
+	deadLetterRepository   repository.DeadLetterRepository
+	deliveryRepository     repository.DeliveryRepository
+	providers              *provider.Registry
+	queue                  contracts.Queue
 	logger                 contracts.Logger
 }
 
 func NewDispatcherUsecase(
 	notificationRepository repository.NotificationRepository,
-	ses contracts.SESIface,
-	webhook contracts.Webhook,
+	deadLetterRepository repository.DeadLetterRepository,
+	deliveryRepository repository.DeliveryRepository,
+	providers *provider.Registry,
+	queue contracts.Queue,
 	logger contracts.Logger,
 ) *DispatcherUsecase {
 	return &DispatcherUsecase{
 		notificationRepository: notificationRepository,
-		webhook:                webhook,
-		ses:                    ses,
+		deadLetterRepository:   deadLetterRepository,
+		deliveryRepository:     deliveryRepository,
+		providers:              providers,
+		queue:                  queue,
 		logger:                 logger,
 	}
 }
 
-func (du *DispatcherUsecase) Execute(message string) (err error) {
-	var successfullyNotifications []string
-	var errorNotifications []string
+// Execute delivers a notification read from the queue. The wait of a
+// requeued notification ends as soon as the consumer stops, while sends run
+// until ctx is cancelled, once the consumer's drain timeout is over. A
+// notification whose sends were cut off is left to be redelivered, and the
+// channels it did reach are skipped then.
+func (du *DispatcherUsecase) Execute(ctx context.Context, message contracts.Message) (err error) {
 	var notification *entity.Notification
-	err = json.Unmarshal([]byte(message), &notification)
+	receivedAt := time.Now()
+
+	err = json.Unmarshal([]byte(message.Value), &notification)
+	if err != nil || notification == nil {
+		return du.deadLetter(message, &entity.Notification{}, fmt.Errorf("invalid notification payload: %v", err), receivedAt)
+	}
+
+	err = readNotificationHeaders(notification, message.Headers)
 	if err != nil {
-		return err
+		return du.deadLetter(message, notification, err, receivedAt)
 	}
 
 	if notification.Retries >= value.MaxRetries {
-		fmt.Errorf("notification retries exceeded")
-	}
-
-	for _, channel := range notification.Channels {
-		if channel.Platform == value.EmailPlatform {
-			err := du.ses.SendEmail(
-				&entity.Email{
-					Recipient: channel.TargetID,
-					Subject:   notification.Title,
-					Body:      notification.Message,
-				},
-			)
-			if err != nil {
-				errorNotifications = append(errorNotifications, err.Error())
-				continue
-			}
-			successfullyNotifications = append(successfullyNotifications, notification.UUID)
+		resumed, err := du.resumeDeadLetter(message, notification)
+		if err != nil || resumed {
+			return err
 		}
+	}
 
-		if channel.Platform == value.DiscordPlatform || channel.Platform == value.SlackPlatform {
-			err := du.httpCall(channel.TargetID, channel.Platform, fmt.Sprintf("%s: %s", notification.Title, notification.Message))
-			if err != nil {
-				errorNotifications = append(errorNotifications, err.Error())
-				continue
-			}
-			successfullyNotifications = append(successfullyNotifications, notification.UUID)
+	if expired(notification, receivedAt) {
+		du.expire(notification)
+		return nil
+	}
+
+	if notification.Retries > value.MaxRetries {
+		err = du.deadLetter(message, notification, fmt.Errorf("notification %s retries exceeded", notification.UUID), receivedAt)
+		if err != nil {
+			return err
 		}
+		du.updateStatus(notification, value.FailedStatus)
+		return nil
 	}
 
-	if len(successfullyNotifications) > 0 {
+	err = du.waitNotBefore(ctx, notification)
+	if err != nil {
+		return err
+	}
+
+	if expired(notification, time.Now()) {
+		du.expire(notification)
+		return nil
+	}
+
+	failedChannels, errorNotifications := du.fanOut(ctx, notification)
+	if ctx.Err() != nil {
+		return fmt.Errorf("stopped delivering notification %s: %w", notification.UUID, ctx.Err())
+	}
+
+	if len(failedChannels) == 0 {
+		du.updateStatus(notification, value.DeliveredStatus)
 		return nil
 	}
 
+	notification.Channels = failedChannels
+
 	if notification.Retries < value.MaxRetries {
-		notification.Retries++
-		serializedMessage, serialError := stringcommon.SerializeToJSON(notification)
-		if serialError != nil {
-			return fmt.Errorf("error serializing notifcation: %w", err)
+		tier := nextRetryTier(notification)
+		if expired(notification, time.Now().Add(tier.Delay)) {
+			du.expire(notification)
+			return nil
+		}
+
+		notification.Errors = append(notification.Errors, strings.Join(errorNotifications, ", "))
+		err = du.requeue(notification, tier, message.Headers[value.TraceIDHeader])
+		if err != nil {
+			return err
+		}
+		du.updateStatus(notification, value.RetryingStatus)
+		return nil
+	}
+
+	serializedMessage, err := stringcommon.SerializeToJSON(notification)
+	if err != nil {
+		return fmt.Errorf("error serializing notifcation: %w", err)
+	}
+
+	err = du.deadLetter(message, notification, errors.New(strings.Join(errorNotifications, ", ")), receivedAt)
+	if err != nil {
+		return err
+	}
+
+	// The dead letter already keeps the errors and is what a retry of this
+	// message resumes from, so failing to record them here is only logged.
+	dbError := du.notificationRepository.CreateNotification(&entity.NotificationError{
+		UUID:  notification.UUID,
+		Body:  serializedMessage,
+		Error: strings.Join(errorNotifications, ", "),
+	})
+	if dbError != nil {
+		du.logger.Errorf(fmt.Sprintf("error creating notification error of %s: %v", notification.UUID, dbError))
+	}
+
+	du.updateStatus(notification, value.FailedStatus)
+	return nil
+}
+
+// updateStatus records the notification lifecycle for producers polling it.
+// The delivery already happened, so a failure here is only logged.
+func (du *DispatcherUsecase) updateStatus(notification *entity.Notification, status string) {
+	err := du.notificationRepository.UpdateRecordStatus(notification.UUID, status, notification.Retries)
+	if err != nil {
+		du.logger.Errorf(fmt.Sprintf("error updating notification %s status: %v", notification.UUID, err))
+	}
+}
+
+// expired reports whether the notification is past its expiry at the given
+// time. Notifications without an expiry never expire.
+func expired(notification *entity.Notification, at time.Time) bool {
+	return notification.ExpiresAt != nil && !at.Before(*notification.ExpiresAt)
+}
+
+// expire drops a notification that would be delivered too late to matter,
+// instead of sending or retrying it.
+func (du *DispatcherUsecase) expire(notification *entity.Notification) {
+	du.logger.Infof(fmt.Sprintf("notification %s expired at %s, dropping it", notification.UUID, notification.ExpiresAt.Format(time.RFC3339)))
+	du.updateStatus(notification, value.ExpiredStatus)
+}
+
+// deliveredChannels returns the channels that already received the
+// notification, so a redelivered message does not notify them twice. When the
+// lookup fails every channel is sent again, preferring a duplicate to a loss.
+func (du *DispatcherUsecase) deliveredChannels(uuid string) map[int]bool {
+	delivered := make(map[int]bool)
+
+	deliveries, err := du.deliveryRepository.GetDeliveriesByUUID(uuid)
+	if err != nil {
+		du.logger.Errorf(fmt.Sprintf("error getting deliveries of %s: %v", uuid, err))
+		return delivered
+	}
+
+	for _, delivery := range deliveries {
+		if delivery.Status == value.SuccessStatus {
+			delivered[delivery.ChannelID] = true
 		}
+	}
+
+	return delivered
+}
 
-		dbError := du.notificationRepository.CreateNotification(&entity.NotificationError{
-			UUID:  notification.UUID,
-			Body:  serializedMessage,
-			Error: strings.Join(errorNotifications, ", "),
-		})
-		if dbError != nil {
-			du.logger.Errorf("error creating notification")
-			return dbError
+// fanOut sends the notification to every channel not delivered yet, with at
+// most GetDispatcherWorkers sends in flight, and returns the channels that
+// failed along with their errors.
+func (du *DispatcherUsecase) fanOut(ctx context.Context, notification *entity.Notification) (map[int]entity.Channel, []string) {
+	var (
+		wg                 sync.WaitGroup
+		mu                 sync.Mutex
+		failedChannels     = make(map[int]entity.Channel)
+		errorNotifications []string
+		workers            = make(chan struct{}, value.GetDispatcherWorkers())
+	)
+
+	delivered := du.deliveredChannels(notification.UUID)
+	for id, channel := range notification.Channels {
+		if ctx.Err() != nil {
+			break
+		}
+		if delivered[channel.ID] {
+			continue
 		}
+
+		wg.Add(1)
+		workers <- struct{}{}
+		go func() {
+			defer func() {
+				<-workers
+				wg.Done()
+			}()
+
+			err := du.deliver(ctx, notification, channel)
+			if err != nil {
+				mu.Lock()
+				errorNotifications = append(errorNotifications, err.Error())
+				failedChannels[id] = channel
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return failedChannels, errorNotifications
+}
+
+// deliver sends the notification to a single channel, keeping its delivery
+// record up to date. Failing to track a delivery does not stop the send.
+func (du *DispatcherUsecase) deliver(ctx context.Context, notification *entity.Notification, channel entity.Channel) error {
+	err := du.deliveryRepository.StartDelivery(notification.UUID, channel.ID)
+	if err != nil {
+		du.logger.Errorf(fmt.Sprintf("error starting delivery of %s to channel %d: %v", notification.UUID, channel.ID, err))
+	}
+
+	status, lastError := value.SuccessStatus, ""
+	via, sendErr := du.send(ctx, notification, channel)
+	if sendErr != nil {
+		status, lastError = value.ErrorStatus, sendErr.Error()
+	}
+
+	err = du.deliveryRepository.FinishDelivery(notification.UUID, channel.ID, status, lastError, via)
+	if err != nil {
+		du.logger.Errorf(fmt.Sprintf("error finishing delivery of %s to channel %d: %v", notification.UUID, channel.ID, err))
+	}
+
+	return sendErr
+}
+
+// send delivers the notification to the channel and returns the upstream
+// service that delivered it, when the provider reports one.
+func (du *DispatcherUsecase) send(ctx context.Context, notification *entity.Notification, channel entity.Channel) (string, error) {
+	provider, err := du.providers.Get(channel.Platform)
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, value.GetChannelTimeout())
+	defer cancel()
+
+	var via string
+	if router, ok := provider.(contracts.Router); ok {
+		via, err = router.SendVia(ctx, channel, notification)
+	} else {
+		err = provider.Send(ctx, channel, notification)
+	}
+	if err != nil && ctx.Err() == context.DeadlineExceeded {
+		return "", fmt.Errorf("%s channel %d timed out after %s: %w", channel.Platform, channel.ID, value.GetChannelTimeout(), err)
+	}
+
+	return via, err
+}
+
+// waitNotBefore holds a requeued notification until its retry delay is over.
+// Every message in a retry topic has the same delay, so blocking the reader
+// never holds back a message that is due earlier. It returns early with an
+// error when the consumer stops or ctx is cancelled, leaving the message to be
+// redelivered.
+func (du *DispatcherUsecase) waitNotBefore(ctx context.Context, notification *entity.Notification) error {
+	if notification.NotBefore == 0 {
+		return nil
+	}
+
+	delay := time.Until(time.Unix(notification.NotBefore, 0))
+	if delay <= 0 {
+		return nil
+	}
+
+	du.logger.Infof(fmt.Sprintf("waiting %s before retrying notification %s", delay, notification.UUID))
+	select {
+	case <-time.After(delay):
+		return nil
+	case <-contracts.Stopping(ctx):
+		return fmt.Errorf("stopped waiting to retry notification %s: %w", notification.UUID, context.Canceled)
+	case <-ctx.Done():
+		return fmt.Errorf("stopped waiting to retry notification %s: %w", notification.UUID, ctx.Err())
+	}
+}
+
+// nextRetryTier returns the retry topic the notification goes to after its
+// current attempt.
+func nextRetryTier(notification *entity.Notification) value.RetryTier {
+	tiers := value.GetRetryTiers()
+	if int(notification.Retries) < len(tiers) {
+		return tiers[notification.Retries]
+	}
+	return tiers[len(tiers)-1]
+}
+
+func (du *DispatcherUsecase) requeue(notification *entity.Notification, tier value.RetryTier, traceID string) error {
+	notification.Retries++
+	notification.NotBefore = time.Now().Add(tier.Delay).Unix()
+
+	serializedMessage, err := stringcommon.SerializeToJSON(notification)
+	if err != nil {
+		return fmt.Errorf("error serializing notifcation: %w", err)
+	}
+
+	du.logger.Infof(fmt.Sprintf("requeueing notification %s to %s", notification.UUID, tier.Topic))
+	err = du.queue.Produce(tier.Topic, newNotificationMessage(notification, serializedMessage, traceID))
+	if err != nil {
+		return fmt.Errorf("error requeueing notification: %w", err)
 	}
 
 	return nil
 }
 
-func (du *DispatcherUsecase) httpCall(url, platform, message string) error {
-	payload := make(map[string]string)
+// deadLetter stores a notification that will not be delivered, along with
+// the errors of every attempt, so it can be inspected and re-driven by an
+// admin, and publishes it to the dead-letter topic. It is stored first, so a
+// retry of the message after a failed publish resumes from it instead of
+// sending to the channels again.
+func (du *DispatcherUsecase) deadLetter(message contracts.Message, notification *entity.Notification, cause error, receivedAt time.Time) error {
+	deadLetter := &entity.DeadLetter{
+		UUID:       notification.UUID,
+		Topic:      value.GetTopic(),
+		Payload:    message.Value,
+		Errors:     append(notification.Errors, cause.Error()),
+		Attempts:   notification.Retries + 1,
+		ReceivedAt: receivedAt,
+		FailedAt:   time.Now(),
+	}
 
-	if platform == value.DiscordPlatform {
-		payload["content"] = message
+	du.logger.Errorf(fmt.Sprintf("sending notification %s to dead-letter topic: %v", notification.UUID, cause))
+	err := du.deadLetterRepository.CreateDeadLetter(deadLetter)
+	if err != nil && !errors.Is(err, repository.ErrDuplicateKey) {
+		return fmt.Errorf("error storing dead letter: %w", err)
 	}
 
-	if platform == value.SlackPlatform {
-		payload["text"] = message
+	return du.publishDeadLetter(message, deadLetter)
+}
+
+// resumeDeadLetter finishes a message whose dead letter was stored by an
+// earlier run that failed before publishing it. It reports whether there was
+// one, in which case the channels are not sent to again.
+func (du *DispatcherUsecase) resumeDeadLetter(message contracts.Message, notification *entity.Notification) (bool, error) {
+	deadLetter, err := du.deadLetterRepository.GetPendingDeadLetter(notification.UUID, notification.Retries+1)
+	if err != nil {
+		return false, fmt.Errorf("error getting dead letter of %s: %w", notification.UUID, err)
+	}
+	if deadLetter == nil {
+		return false, nil
 	}
 
-	body, err := json.Marshal(payload)
+	du.logger.Infof(fmt.Sprintf("notification %s was already dead-lettered, publishing it again", notification.UUID))
+	err = du.publishDeadLetter(message, deadLetter)
 	if err != nil {
-		return err
+		return false, err
 	}
 
-	resp, err := du.webhook.Post(url, "application/json", bytes.NewBuffer(body))
+	du.updateStatus(notification, value.FailedStatus)
+	return true, nil
+}
+
+func (du *DispatcherUsecase) publishDeadLetter(message contracts.Message, deadLetter *entity.DeadLetter) error {
+	serializedDeadLetter, err := stringcommon.SerializeToJSON(deadLetter)
 	if err != nil {
 		return err
 	}
-	defer resp.Close()
 
-	if resp.StatusCode >= 300 {
-		return fmt.Errorf("slack webhook returned status code %d", resp.StatusCode)
+	err = du.queue.Produce(value.GetDeadLetterTopic(), contracts.Message{
+		Key:   deadLetter.UUID,
+		Value: string(serializedDeadLetter),
+		Headers: map[string]string{
+			value.TraceIDHeader:       message.Headers[value.TraceIDHeader],
+			value.SchemaVersionHeader: value.NotificationSchemaVersion,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error publishing dead letter: %w", err)
 	}
 
 	return nil
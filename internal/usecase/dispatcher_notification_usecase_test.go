@@ -1,18 +1,26 @@
 package usecase
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
+	"github.com/gurodrigues-dev/notifier-app/internal/entity"
 	"github.com/gurodrigues-dev/notifier-app/internal/infra/contracts"
+	"github.com/gurodrigues-dev/notifier-app/internal/infra/provider"
+	"github.com/gurodrigues-dev/notifier-app/internal/value"
 	"github.com/gurodrigues-dev/notifier-app/mocks"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
 func TestDispatcherUsecase_Execute(t *testing.T) {
 	type args struct {
-		message string
+		message contracts.Message
 	}
 	tests := []struct {
 		name    string
@@ -23,122 +31,194 @@ func TestDispatcherUsecase_Execute(t *testing.T) {
 		{
 			name: "there return is to success",
 			args: args{
-				message: `{
-					"id": 123,
-					"uuid": "550e8400-e29b-41d4-a716-446655440000",
-					"title": "Order Confirmation",
-					"message": "Your order #12345 has been confirmed.",
-					"channels": {
-						"1": {
-							"id": 1,
-							"platform": "email",
-							"target_id": "user@example.com",
-							"group": "customers"
+				message: contracts.Message{
+					Value: `{
+						"id": 123,
+						"uuid": "550e8400-e29b-41d4-a716-446655440000",
+						"title": "Order Confirmation",
+						"message": "Your order #12345 has been confirmed.",
+						"channels": {
+							"1": {
+								"id": 1,
+								"platform": "email",
+								"target_id": "user@example.com",
+								"group": "customers"
+							},
+							"2": {
+								"id": 2,
+								"platform": "slack",
+								"target_id": "webhook_url",
+								"group": "customers"
+							}
 						},
-						"2": {
-							"id": 2,
-							"platform": "slack",
-							"target_id": "webhook_url",
-							"group": "customers"
+						"event": {
+							"name": "OrderPlaced",
+							"currency": "BRL",
+							"requester": "system",
+							"receiver": "user",
+							"category": "ecommerce",
+							"timestamp": 1716720000,
+							"cost_cents": 5000
 						}
-					},
-					"event": {
-						"name": "OrderPlaced",
-						"currency": "BRL",
-						"requester": "system",
-						"receiver": "user",
-						"category": "ecommerce",
-						"timestamp": 1716720000,
-						"cost_cents": 5000
-					},
-					"retries": 0
-				}`,
+					}`,
+					Headers: map[string]string{value.RetriesHeader: "0"},
+				},
 			},
 			setup: func(t *testing.T) *DispatcherUsecase {
 				repository := mocks.NewNotificationRepository(t)
+				deadLetterRepository := mocks.NewDeadLetterRepository(t)
+				deliveryRepository := mocks.NewDeliveryRepository(t)
 				ses := mocks.NewSESIface(t)
 				webhook := mocks.NewWebhook(t)
 				logger := mocks.NewLogger(t)
-				ses.On("SendEmail", mock.Anything).Return(nil)
-				webhook.On("Post", mock.Anything, mock.Anything, mock.Anything).
+				queue := mocks.NewQueue(t)
+
+				deliveryRepository.On("GetDeliveriesByUUID", mock.Anything).Return([]entity.Delivery{}, nil)
+				deliveryRepository.On("StartDelivery", "550e8400-e29b-41d4-a716-446655440000", mock.Anything).Return(nil)
+				deliveryRepository.On("FinishDelivery", "550e8400-e29b-41d4-a716-446655440000", mock.Anything, value.SuccessStatus, mock.Anything, mock.Anything).Return(nil)
+				ses.On("SendEmail", mock.Anything, mock.Anything).Return(nil)
+				webhook.On("Post", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 					Return(&contracts.HTTPResponse{
 						StatusCode: 200,
 						Close: func() error {
 							return nil
 						},
 					}, nil)
+				repository.On("UpdateRecordStatus", mock.Anything, value.DeliveredStatus, mock.Anything).Return(nil)
 				return NewDispatcherUsecase(
 					repository,
-					ses,
-					webhook,
+					deadLetterRepository,
+					deliveryRepository,
+					newTestRegistry(ses, webhook),
+					queue,
 					logger,
 				)
 			},
 			wantErr: false,
 		},
 		{
-			name: "there return is serialize error",
+			name: "there return is invalid payload sent to dead letter",
 			args: args{
-				message: "",
+				message: contracts.Message{},
 			},
 			setup: func(t *testing.T) *DispatcherUsecase {
 				repository := mocks.NewNotificationRepository(t)
+				deadLetterRepository := mocks.NewDeadLetterRepository(t)
+				deliveryRepository := mocks.NewDeliveryRepository(t)
 				ses := mocks.NewSESIface(t)
 				webhook := mocks.NewWebhook(t)
 				logger := mocks.NewLogger(t)
+				queue := mocks.NewQueue(t)
+
+				logger.On("Errorf", mock.Anything).Return()
+				queue.On("Produce", value.GetDeadLetterTopic(), mock.Anything).Return(nil)
+				deadLetterRepository.On("CreateDeadLetter", mock.Anything).Return(nil)
 
 				return NewDispatcherUsecase(
 					repository,
-					ses,
-					webhook,
+					deadLetterRepository,
+					deliveryRepository,
+					newTestRegistry(ses, webhook),
+					queue,
 					logger,
 				)
 			},
-			wantErr: true,
+			wantErr: false,
+		},
+		{
+			name: "there return is invalid retries header sent to dead letter",
+			args: args{
+				message: contracts.Message{
+					Value:   `{"uuid": "550e8400-e29b-41d4-a716-446655440000"}`,
+					Headers: map[string]string{value.RetriesHeader: "three"},
+				},
+			},
+			setup: func(t *testing.T) *DispatcherUsecase {
+				repository := mocks.NewNotificationRepository(t)
+				deadLetterRepository := mocks.NewDeadLetterRepository(t)
+				deliveryRepository := mocks.NewDeliveryRepository(t)
+				ses := mocks.NewSESIface(t)
+				webhook := mocks.NewWebhook(t)
+				logger := mocks.NewLogger(t)
+				queue := mocks.NewQueue(t)
+
+				logger.On("Errorf", mock.Anything).Return()
+				queue.On("Produce", value.GetDeadLetterTopic(), mock.MatchedBy(func(message contracts.Message) bool {
+					return message.Key == "550e8400-e29b-41d4-a716-446655440000"
+				})).Return(nil)
+				deadLetterRepository.On("CreateDeadLetter", mock.Anything).Return(nil)
+
+				return NewDispatcherUsecase(
+					repository,
+					deadLetterRepository,
+					deliveryRepository,
+					newTestRegistry(ses, webhook),
+					queue,
+					logger,
+				)
+			},
+			wantErr: false,
 		},
 		{
-			name: "there return is retries greater than 3",
+			name: "there return is retries exceeded sent to dead letter",
 			args: args{
-				message: `{
-					"retries": 4
-				}`,
+				message: contracts.Message{
+					Value:   `{}`,
+					Headers: map[string]string{value.RetriesHeader: "4"},
+				},
 			},
 			setup: func(t *testing.T) *DispatcherUsecase {
 				repository := mocks.NewNotificationRepository(t)
+				deadLetterRepository := mocks.NewDeadLetterRepository(t)
+				deliveryRepository := mocks.NewDeliveryRepository(t)
 				ses := mocks.NewSESIface(t)
 				webhook := mocks.NewWebhook(t)
 				logger := mocks.NewLogger(t)
+				queue := mocks.NewQueue(t)
 
-				repository.On("CreateNotification", mock.Anything).Maybe().Return(nil)
+				logger.On("Errorf", mock.Anything).Return()
+				queue.On("Produce", value.GetDeadLetterTopic(), mock.Anything).Return(nil)
+				deadLetterRepository.On("GetPendingDeadLetter", "", int64(5)).Return(nil, nil)
+				deadLetterRepository.On("CreateDeadLetter", mock.Anything).Return(nil)
+				repository.On("UpdateRecordStatus", mock.Anything, value.FailedStatus, mock.Anything).Return(nil)
 
 				return NewDispatcherUsecase(
 					repository,
-					ses,
-					webhook,
+					deadLetterRepository,
+					deliveryRepository,
+					newTestRegistry(ses, webhook),
+					queue,
 					logger,
 				)
 			},
 			wantErr: false,
 		},
 		{
-			name: "when platform not have accessbility",
+			name: "when there is no channel to deliver",
 			args: args{
-				message: `{
-					"retries": 0
-				}`,
+				message: contracts.Message{
+					Value:   `{}`,
+					Headers: map[string]string{value.RetriesHeader: "0"},
+				},
 			},
 			setup: func(t *testing.T) *DispatcherUsecase {
 				repository := mocks.NewNotificationRepository(t)
+				deadLetterRepository := mocks.NewDeadLetterRepository(t)
+				deliveryRepository := mocks.NewDeliveryRepository(t)
 				ses := mocks.NewSESIface(t)
 				webhook := mocks.NewWebhook(t)
 				logger := mocks.NewLogger(t)
+				queue := mocks.NewQueue(t)
 
-				repository.On("CreateNotification", mock.Anything).Maybe().Return(nil)
+				deliveryRepository.On("GetDeliveriesByUUID", mock.Anything).Return([]entity.Delivery{}, nil)
+				repository.On("UpdateRecordStatus", mock.Anything, value.DeliveredStatus, mock.Anything).Return(nil)
 
 				return NewDispatcherUsecase(
 					repository,
-					ses,
-					webhook,
+					deadLetterRepository,
+					deliveryRepository,
+					newTestRegistry(ses, webhook),
+					queue,
 					logger,
 				)
 			},
@@ -147,50 +227,62 @@ func TestDispatcherUsecase_Execute(t *testing.T) {
 		{
 			name: "there is return to webhook status 500",
 			args: args{
-				message: `{
-					"id": 123,
-					"uuid": "550e8400-e29b-41d4-a716-446655440000",
-					"title": "Order Confirmation",
-					"message": "Your order #12345 has been confirmed.",
-					"channels": {
-						"2": {
-							"id": 2,
-							"platform": "slack",
-							"target_id": "webhook_url",
-							"group": "customers"
+				message: contracts.Message{
+					Value: `{
+						"id": 123,
+						"uuid": "550e8400-e29b-41d4-a716-446655440000",
+						"title": "Order Confirmation",
+						"message": "Your order #12345 has been confirmed.",
+						"channels": {
+							"2": {
+								"id": 2,
+								"platform": "slack",
+								"target_id": "webhook_url",
+								"group": "customers"
+							}
+						},
+						"event": {
+							"name": "OrderPlaced",
+							"currency": "BRL",
+							"requester": "system",
+							"receiver": "user",
+							"category": "ecommerce",
+							"timestamp": 1716720000,
+							"cost_cents": 5000
 						}
-					},
-					"event": {
-						"name": "OrderPlaced",
-						"currency": "BRL",
-						"requester": "system",
-						"receiver": "user",
-						"category": "ecommerce",
-						"timestamp": 1716720000,
-						"cost_cents": 5000
-					},
-					"retries": 0
-				}`,
+					}`,
+					Headers: map[string]string{value.RetriesHeader: "0"},
+				},
 			},
 			setup: func(t *testing.T) *DispatcherUsecase {
 				repository := mocks.NewNotificationRepository(t)
+				deadLetterRepository := mocks.NewDeadLetterRepository(t)
+				deliveryRepository := mocks.NewDeliveryRepository(t)
 				ses := mocks.NewSESIface(t)
 				webhook := mocks.NewWebhook(t)
 				logger := mocks.NewLogger(t)
+				queue := mocks.NewQueue(t)
 
-				webhook.On("Post", mock.Anything, mock.Anything, mock.Anything).
+				deliveryRepository.On("GetDeliveriesByUUID", mock.Anything).Return([]entity.Delivery{}, nil)
+				deliveryRepository.On("StartDelivery", "550e8400-e29b-41d4-a716-446655440000", mock.Anything).Return(nil)
+				deliveryRepository.On("FinishDelivery", "550e8400-e29b-41d4-a716-446655440000", mock.Anything, value.ErrorStatus, mock.Anything, mock.Anything).Return(nil)
+				webhook.On("Post", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 					Return(&contracts.HTTPResponse{
 						StatusCode: 500,
 						Close: func() error {
 							return nil
 						},
 					}, nil)
-				repository.On("CreateNotification", mock.Anything).Maybe().Return(nil)
+				logger.On("Infof", mock.Anything).Return()
+				queue.On("Produce", mock.Anything, mock.Anything).Return(nil)
+				repository.On("UpdateRecordStatus", mock.Anything, value.RetryingStatus, mock.Anything).Return(nil)
 
 				return NewDispatcherUsecase(
 					repository,
-					ses,
-					webhook,
+					deadLetterRepository,
+					deliveryRepository,
+					newTestRegistry(ses, webhook),
+					queue,
 					logger,
 				)
 			},
@@ -199,50 +291,62 @@ func TestDispatcherUsecase_Execute(t *testing.T) {
 		{
 			name: "there is return to webhook slack error",
 			args: args{
-				message: `{
-					"id": 123,
-					"uuid": "550e8400-e29b-41d4-a716-446655440000",
-					"title": "Order Confirmation",
-					"message": "Your order #12345 has been confirmed.",
-					"channels": {
-						"2": {
-							"id": 2,
-							"platform": "slack",
-							"target_id": "webhook_url",
-							"group": "customers"
+				message: contracts.Message{
+					Value: `{
+						"id": 123,
+						"uuid": "550e8400-e29b-41d4-a716-446655440000",
+						"title": "Order Confirmation",
+						"message": "Your order #12345 has been confirmed.",
+						"channels": {
+							"2": {
+								"id": 2,
+								"platform": "slack",
+								"target_id": "webhook_url",
+								"group": "customers"
+							}
+						},
+						"event": {
+							"name": "OrderPlaced",
+							"currency": "BRL",
+							"requester": "system",
+							"receiver": "user",
+							"category": "ecommerce",
+							"timestamp": 1716720000,
+							"cost_cents": 5000
 						}
-					},
-					"event": {
-						"name": "OrderPlaced",
-						"currency": "BRL",
-						"requester": "system",
-						"receiver": "user",
-						"category": "ecommerce",
-						"timestamp": 1716720000,
-						"cost_cents": 5000
-					},
-					"retries": 0
-				}`,
+					}`,
+					Headers: map[string]string{value.RetriesHeader: "0"},
+				},
 			},
 			setup: func(t *testing.T) *DispatcherUsecase {
 				repository := mocks.NewNotificationRepository(t)
+				deadLetterRepository := mocks.NewDeadLetterRepository(t)
+				deliveryRepository := mocks.NewDeliveryRepository(t)
 				ses := mocks.NewSESIface(t)
 				webhook := mocks.NewWebhook(t)
 				logger := mocks.NewLogger(t)
+				queue := mocks.NewQueue(t)
 
-				webhook.On("Post", mock.Anything, mock.Anything, mock.Anything).
+				deliveryRepository.On("GetDeliveriesByUUID", mock.Anything).Return([]entity.Delivery{}, nil)
+				deliveryRepository.On("StartDelivery", "550e8400-e29b-41d4-a716-446655440000", mock.Anything).Return(nil)
+				deliveryRepository.On("FinishDelivery", "550e8400-e29b-41d4-a716-446655440000", mock.Anything, value.ErrorStatus, mock.Anything, mock.Anything).Return(nil)
+				webhook.On("Post", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 					Return(&contracts.HTTPResponse{
 						StatusCode: 500,
 						Close: func() error {
 							return nil
 						},
 					}, errors.New("webhook error"))
-				repository.On("CreateNotification", mock.Anything).Maybe().Return(nil)
+				logger.On("Infof", mock.Anything).Return()
+				queue.On("Produce", mock.Anything, mock.Anything).Return(nil)
+				repository.On("UpdateRecordStatus", mock.Anything, value.RetryingStatus, mock.Anything).Return(nil)
 
 				return NewDispatcherUsecase(
 					repository,
-					ses,
-					webhook,
+					deadLetterRepository,
+					deliveryRepository,
+					newTestRegistry(ses, webhook),
+					queue,
 					logger,
 				)
 			},
@@ -251,50 +355,62 @@ func TestDispatcherUsecase_Execute(t *testing.T) {
 		{
 			name: "there is return to webhook discrd error",
 			args: args{
-				message: `{
-					"id": 123,
-					"uuid": "550e8400-e29b-41d4-a716-446655440000",
-					"title": "Order Confirmation",
-					"message": "Your order #12345 has been confirmed.",
-					"channels": {
-						"2": {
-							"id": 2,
-							"platform": "discord",
-							"target_id": "webhook_url",
-							"group": "customers"
+				message: contracts.Message{
+					Value: `{
+						"id": 123,
+						"uuid": "550e8400-e29b-41d4-a716-446655440000",
+						"title": "Order Confirmation",
+						"message": "Your order #12345 has been confirmed.",
+						"channels": {
+							"2": {
+								"id": 2,
+								"platform": "discord",
+								"target_id": "webhook_url",
+								"group": "customers"
+							}
+						},
+						"event": {
+							"name": "OrderPlaced",
+							"currency": "BRL",
+							"requester": "system",
+							"receiver": "user",
+							"category": "ecommerce",
+							"timestamp": 1716720000,
+							"cost_cents": 5000
 						}
-					},
-					"event": {
-						"name": "OrderPlaced",
-						"currency": "BRL",
-						"requester": "system",
-						"receiver": "user",
-						"category": "ecommerce",
-						"timestamp": 1716720000,
-						"cost_cents": 5000
-					},
-					"retries": 0
-				}`,
+					}`,
+					Headers: map[string]string{value.RetriesHeader: "0"},
+				},
 			},
 			setup: func(t *testing.T) *DispatcherUsecase {
 				repository := mocks.NewNotificationRepository(t)
+				deadLetterRepository := mocks.NewDeadLetterRepository(t)
+				deliveryRepository := mocks.NewDeliveryRepository(t)
 				ses := mocks.NewSESIface(t)
 				webhook := mocks.NewWebhook(t)
 				logger := mocks.NewLogger(t)
+				queue := mocks.NewQueue(t)
 
-				webhook.On("Post", mock.Anything, mock.Anything, mock.Anything).
+				deliveryRepository.On("GetDeliveriesByUUID", mock.Anything).Return([]entity.Delivery{}, nil)
+				deliveryRepository.On("StartDelivery", "550e8400-e29b-41d4-a716-446655440000", mock.Anything).Return(nil)
+				deliveryRepository.On("FinishDelivery", "550e8400-e29b-41d4-a716-446655440000", mock.Anything, value.ErrorStatus, mock.Anything, mock.Anything).Return(nil)
+				webhook.On("Post", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 					Return(&contracts.HTTPResponse{
 						StatusCode: 500,
 						Close: func() error {
 							return nil
 						},
 					}, errors.New("webhook error"))
-				repository.On("CreateNotification", mock.Anything).Maybe().Return(nil)
+				logger.On("Infof", mock.Anything).Return()
+				queue.On("Produce", mock.Anything, mock.Anything).Return(nil)
+				repository.On("UpdateRecordStatus", mock.Anything, value.RetryingStatus, mock.Anything).Return(nil)
 
 				return NewDispatcherUsecase(
 					repository,
-					ses,
-					webhook,
+					deadLetterRepository,
+					deliveryRepository,
+					newTestRegistry(ses, webhook),
+					queue,
 					logger,
 				)
 			},
@@ -303,101 +419,758 @@ func TestDispatcherUsecase_Execute(t *testing.T) {
 		{
 			name: "there is return to email error",
 			args: args{
-				message: `{
-					"id": 123,
-					"uuid": "550e8400-e29b-41d4-a716-446655440000",
-					"title": "Order Confirmation",
-					"message": "Your order #12345 has been confirmed.",
-					"channels": {
-						"1": {
-							"id": 1,
-							"platform": "email",
-							"target_id": "webhook@gmail.com",
-							"group": "customers"
+				message: contracts.Message{
+					Value: `{
+						"id": 123,
+						"uuid": "550e8400-e29b-41d4-a716-446655440000",
+						"title": "Order Confirmation",
+						"message": "Your order #12345 has been confirmed.",
+						"channels": {
+							"1": {
+								"id": 1,
+								"platform": "email",
+								"target_id": "webhook@gmail.com",
+								"group": "customers"
+							}
+						},
+						"event": {
+							"name": "OrderPlaced",
+							"currency": "BRL",
+							"requester": "system",
+							"receiver": "user",
+							"category": "ecommerce",
+							"timestamp": 1716720000,
+							"cost_cents": 5000
 						}
-					},
-					"event": {
-						"name": "OrderPlaced",
-						"currency": "BRL",
-						"requester": "system",
-						"receiver": "user",
-						"category": "ecommerce",
-						"timestamp": 1716720000,
-						"cost_cents": 5000
-					},
-					"retries": 0
-				}`,
+					}`,
+					Headers: map[string]string{value.RetriesHeader: "0"},
+				},
 			},
 			setup: func(t *testing.T) *DispatcherUsecase {
 				repository := mocks.NewNotificationRepository(t)
+				deadLetterRepository := mocks.NewDeadLetterRepository(t)
+				deliveryRepository := mocks.NewDeliveryRepository(t)
 				ses := mocks.NewSESIface(t)
 				webhook := mocks.NewWebhook(t)
 				logger := mocks.NewLogger(t)
+				queue := mocks.NewQueue(t)
 
-				ses.On("SendEmail", mock.Anything).Return(errors.New("email error"))
-				repository.On("CreateNotification", mock.Anything).Maybe().Return(nil)
+				deliveryRepository.On("GetDeliveriesByUUID", mock.Anything).Return([]entity.Delivery{}, nil)
+				deliveryRepository.On("StartDelivery", "550e8400-e29b-41d4-a716-446655440000", mock.Anything).Return(nil)
+				deliveryRepository.On("FinishDelivery", "550e8400-e29b-41d4-a716-446655440000", mock.Anything, value.ErrorStatus, mock.Anything, mock.Anything).Return(nil)
+				ses.On("SendEmail", mock.Anything, mock.Anything).Return(errors.New("email error"))
+				logger.On("Infof", mock.Anything).Return()
+				queue.On("Produce", mock.Anything, mock.Anything).Return(nil)
+				repository.On("UpdateRecordStatus", mock.Anything, value.RetryingStatus, mock.Anything).Return(nil)
 
 				return NewDispatcherUsecase(
 					repository,
-					ses,
-					webhook,
+					deadLetterRepository,
+					deliveryRepository,
+					newTestRegistry(ses, webhook),
+					queue,
 					logger,
 				)
 			},
 			wantErr: false,
 		},
 		{
-			name: "when final return to database fails",
+			name: "when storing the dead letter fails",
 			args: args{
-				message: `{
-					"id": 123,
-					"uuid": "550e8400-e29b-41d4-a716-446655440000",
-					"title": "Order Confirmation",
-					"message": "Your order #12345 has been confirmed.",
-					"channels": {
-						"1": {
-							"id": 1,
-							"platform": "email",
-							"target_id": "webhook@gmail.com",
-							"group": "customers"
+				message: contracts.Message{
+					Value: `{
+						"id": 123,
+						"uuid": "550e8400-e29b-41d4-a716-446655440000",
+						"title": "Order Confirmation",
+						"message": "Your order #12345 has been confirmed.",
+						"channels": {
+							"1": {
+								"id": 1,
+								"platform": "email",
+								"target_id": "webhook@gmail.com",
+								"group": "customers"
+							}
+						},
+						"event": {
+							"name": "OrderPlaced",
+							"currency": "BRL",
+							"requester": "system",
+							"receiver": "user",
+							"category": "ecommerce",
+							"timestamp": 1716720000,
+							"cost_cents": 5000
 						}
-					},
-					"event": {
-						"name": "OrderPlaced",
-						"currency": "BRL",
-						"requester": "system",
-						"receiver": "user",
-						"category": "ecommerce",
-						"timestamp": 1716720000,
-						"cost_cents": 5000
-					},
-					"retries": 0
-				}`,
+					}`,
+					Headers: map[string]string{value.RetriesHeader: "3"},
+				},
 			},
 			setup: func(t *testing.T) *DispatcherUsecase {
 				repository := mocks.NewNotificationRepository(t)
+				deadLetterRepository := mocks.NewDeadLetterRepository(t)
+				deliveryRepository := mocks.NewDeliveryRepository(t)
 				ses := mocks.NewSESIface(t)
 				webhook := mocks.NewWebhook(t)
 				logger := mocks.NewLogger(t)
+				queue := mocks.NewQueue(t)
 
-				ses.On("SendEmail", mock.Anything).Return(errors.New("email error"))
-				repository.On("CreateNotification", mock.Anything).Return(errors.New("db error"))
+				deliveryRepository.On("GetDeliveriesByUUID", mock.Anything).Return([]entity.Delivery{}, nil)
+				deliveryRepository.On("StartDelivery", "550e8400-e29b-41d4-a716-446655440000", mock.Anything).Return(nil)
+				deliveryRepository.On("FinishDelivery", "550e8400-e29b-41d4-a716-446655440000", mock.Anything, value.ErrorStatus, mock.Anything, mock.Anything).Return(nil)
+				ses.On("SendEmail", mock.Anything, mock.Anything).Return(errors.New("email error"))
+				deadLetterRepository.On("GetPendingDeadLetter", "550e8400-e29b-41d4-a716-446655440000", int64(4)).Return(nil, nil)
+				deadLetterRepository.On("CreateDeadLetter", mock.Anything).Return(errors.New("db error"))
 				logger.On("Errorf", mock.Anything, mock.Anything).Return()
 
 				return NewDispatcherUsecase(
 					repository,
-					ses,
-					webhook,
+					deadLetterRepository,
+					deliveryRepository,
+					newTestRegistry(ses, webhook),
+					queue,
+					logger,
+				)
+			},
+			wantErr: true,
+		},
+		{
+			name: "when retries are exhausted the error is recorded and sent to dead letter",
+			args: args{
+				message: contracts.Message{
+					Value: `{
+						"id": 123,
+						"uuid": "550e8400-e29b-41d4-a716-446655440000",
+						"title": "Order Confirmation",
+						"message": "Your order #12345 has been confirmed.",
+						"channels": {
+							"1": {
+								"id": 1,
+								"platform": "email",
+								"target_id": "webhook@gmail.com",
+								"group": "customers"
+							}
+						},
+						"event": {
+							"name": "OrderPlaced",
+							"currency": "BRL",
+							"requester": "system",
+							"receiver": "user",
+							"category": "ecommerce",
+							"timestamp": 1716720000,
+							"cost_cents": 5000
+						}
+					}`,
+					Headers: map[string]string{value.RetriesHeader: "3"},
+				},
+			},
+			setup: func(t *testing.T) *DispatcherUsecase {
+				repository := mocks.NewNotificationRepository(t)
+				deadLetterRepository := mocks.NewDeadLetterRepository(t)
+				deliveryRepository := mocks.NewDeliveryRepository(t)
+				ses := mocks.NewSESIface(t)
+				webhook := mocks.NewWebhook(t)
+				logger := mocks.NewLogger(t)
+				queue := mocks.NewQueue(t)
+
+				deliveryRepository.On("GetDeliveriesByUUID", mock.Anything).Return([]entity.Delivery{}, nil)
+				deliveryRepository.On("StartDelivery", "550e8400-e29b-41d4-a716-446655440000", mock.Anything).Return(nil)
+				deliveryRepository.On("FinishDelivery", "550e8400-e29b-41d4-a716-446655440000", mock.Anything, value.ErrorStatus, mock.Anything, mock.Anything).Return(nil)
+				ses.On("SendEmail", mock.Anything, mock.Anything).Return(errors.New("email error"))
+				repository.On("CreateNotification", mock.Anything).Return(nil)
+				logger.On("Errorf", mock.Anything).Return()
+				queue.On("Produce", value.GetDeadLetterTopic(), mock.Anything).Return(nil)
+				deadLetterRepository.On("GetPendingDeadLetter", "550e8400-e29b-41d4-a716-446655440000", int64(4)).Return(nil, nil)
+				deadLetterRepository.On("CreateDeadLetter", mock.Anything).Return(nil)
+				repository.On("UpdateRecordStatus", mock.Anything, value.FailedStatus, mock.Anything).Return(nil)
+
+				return NewDispatcherUsecase(
+					repository,
+					deadLetterRepository,
+					deliveryRepository,
+					newTestRegistry(ses, webhook),
+					queue,
+					logger,
+				)
+			},
+			wantErr: false,
+		},
+		{
+			name: "when requeue to retry topic fails",
+			args: args{
+				message: contracts.Message{
+					Value: `{
+						"id": 123,
+						"uuid": "550e8400-e29b-41d4-a716-446655440000",
+						"title": "Order Confirmation",
+						"message": "Your order #12345 has been confirmed.",
+						"channels": {
+							"1": {
+								"id": 1,
+								"platform": "email",
+								"target_id": "webhook@gmail.com",
+								"group": "customers"
+							}
+						},
+						"event": {
+							"name": "OrderPlaced",
+							"currency": "BRL",
+							"requester": "system",
+							"receiver": "user",
+							"category": "ecommerce",
+							"timestamp": 1716720000,
+							"cost_cents": 5000
+						}
+					}`,
+					Headers: map[string]string{value.RetriesHeader: "1"},
+				},
+			},
+			setup: func(t *testing.T) *DispatcherUsecase {
+				repository := mocks.NewNotificationRepository(t)
+				deadLetterRepository := mocks.NewDeadLetterRepository(t)
+				deliveryRepository := mocks.NewDeliveryRepository(t)
+				ses := mocks.NewSESIface(t)
+				webhook := mocks.NewWebhook(t)
+				logger := mocks.NewLogger(t)
+				queue := mocks.NewQueue(t)
+
+				deliveryRepository.On("GetDeliveriesByUUID", mock.Anything).Return([]entity.Delivery{}, nil)
+				deliveryRepository.On("StartDelivery", "550e8400-e29b-41d4-a716-446655440000", mock.Anything).Return(nil)
+				deliveryRepository.On("FinishDelivery", "550e8400-e29b-41d4-a716-446655440000", mock.Anything, value.ErrorStatus, mock.Anything, mock.Anything).Return(nil)
+				ses.On("SendEmail", mock.Anything, mock.Anything).Return(errors.New("email error"))
+				logger.On("Infof", mock.Anything).Return()
+				queue.On("Produce", value.GetRetryTiers()[1].Topic, mock.Anything).Return(errors.New("kafka error"))
+
+				return NewDispatcherUsecase(
+					repository,
+					deadLetterRepository,
+					deliveryRepository,
+					newTestRegistry(ses, webhook),
+					queue,
+					logger,
+				)
+			},
+			wantErr: true,
+		},
+		{
+			name: "when dead letter publish fails",
+			args: args{
+				message: contracts.Message{Value: "not a json"},
+			},
+			setup: func(t *testing.T) *DispatcherUsecase {
+				repository := mocks.NewNotificationRepository(t)
+				deadLetterRepository := mocks.NewDeadLetterRepository(t)
+				deliveryRepository := mocks.NewDeliveryRepository(t)
+				ses := mocks.NewSESIface(t)
+				webhook := mocks.NewWebhook(t)
+				logger := mocks.NewLogger(t)
+				queue := mocks.NewQueue(t)
+
+				logger.On("Errorf", mock.Anything).Return()
+				deadLetterRepository.On("CreateDeadLetter", mock.Anything).Return(nil)
+				queue.On("Produce", value.GetDeadLetterTopic(), mock.Anything).Return(errors.New("kafka error"))
+
+				return NewDispatcherUsecase(
+					repository,
+					deadLetterRepository,
+					deliveryRepository,
+					newTestRegistry(ses, webhook),
+					queue,
 					logger,
 				)
 			},
 			wantErr: true,
 		},
+		{
+			name: "when the dead letter was already stored it is published without sending again",
+			args: args{
+				message: contracts.Message{
+					Value: `{
+						"uuid": "550e8400-e29b-41d4-a716-446655440000",
+						"channels": {
+							"1": {
+								"id": 1,
+								"platform": "email",
+								"target_id": "webhook@gmail.com",
+								"group": "customers"
+							}
+						}
+					}`,
+					Headers: map[string]string{value.RetriesHeader: "3"},
+				},
+			},
+			setup: func(t *testing.T) *DispatcherUsecase {
+				repository := mocks.NewNotificationRepository(t)
+				deadLetterRepository := mocks.NewDeadLetterRepository(t)
+				deliveryRepository := mocks.NewDeliveryRepository(t)
+				ses := mocks.NewSESIface(t)
+				webhook := mocks.NewWebhook(t)
+				logger := mocks.NewLogger(t)
+				queue := mocks.NewQueue(t)
+
+				deadLetterRepository.On("GetPendingDeadLetter", "550e8400-e29b-41d4-a716-446655440000", int64(4)).Return(&entity.DeadLetter{
+					UUID:     "550e8400-e29b-41d4-a716-446655440000",
+					Attempts: 4,
+				}, nil)
+				logger.On("Infof", mock.Anything).Return()
+				queue.On("Produce", value.GetDeadLetterTopic(), mock.MatchedBy(func(message contracts.Message) bool {
+					return message.Key == "550e8400-e29b-41d4-a716-446655440000"
+				})).Return(nil).Once()
+				repository.On("UpdateRecordStatus", "550e8400-e29b-41d4-a716-446655440000", value.FailedStatus, int64(3)).Return(nil)
+
+				return NewDispatcherUsecase(
+					repository,
+					deadLetterRepository,
+					deliveryRepository,
+					newTestRegistry(ses, webhook),
+					queue,
+					logger,
+				)
+			},
+			wantErr: false,
+		},
+		{
+			name: "when recording the errors fails the dead letter is still sent",
+			args: args{
+				message: contracts.Message{
+					Value: `{
+						"uuid": "550e8400-e29b-41d4-a716-446655440000",
+						"channels": {
+							"1": {
+								"id": 1,
+								"platform": "email",
+								"target_id": "webhook@gmail.com",
+								"group": "customers"
+							}
+						}
+					}`,
+					Headers: map[string]string{value.RetriesHeader: "3"},
+				},
+			},
+			setup: func(t *testing.T) *DispatcherUsecase {
+				repository := mocks.NewNotificationRepository(t)
+				deadLetterRepository := mocks.NewDeadLetterRepository(t)
+				deliveryRepository := mocks.NewDeliveryRepository(t)
+				ses := mocks.NewSESIface(t)
+				webhook := mocks.NewWebhook(t)
+				logger := mocks.NewLogger(t)
+				queue := mocks.NewQueue(t)
+
+				deliveryRepository.On("GetDeliveriesByUUID", mock.Anything).Return([]entity.Delivery{}, nil)
+				deliveryRepository.On("StartDelivery", "550e8400-e29b-41d4-a716-446655440000", mock.Anything).Return(nil)
+				deliveryRepository.On("FinishDelivery", "550e8400-e29b-41d4-a716-446655440000", mock.Anything, value.ErrorStatus, mock.Anything, mock.Anything).Return(nil)
+				ses.On("SendEmail", mock.Anything, mock.Anything).Return(errors.New("email error"))
+				deadLetterRepository.On("GetPendingDeadLetter", "550e8400-e29b-41d4-a716-446655440000", int64(4)).Return(nil, nil)
+				deadLetterRepository.On("CreateDeadLetter", mock.Anything).Return(nil)
+				queue.On("Produce", value.GetDeadLetterTopic(), mock.Anything).Return(nil)
+				repository.On("CreateNotification", mock.Anything).Return(errors.New("db error"))
+				repository.On("UpdateRecordStatus", mock.Anything, value.FailedStatus, mock.Anything).Return(nil)
+				logger.On("Errorf", mock.Anything).Return()
+
+				return NewDispatcherUsecase(
+					repository,
+					deadLetterRepository,
+					deliveryRepository,
+					newTestRegistry(ses, webhook),
+					queue,
+					logger,
+				)
+			},
+			wantErr: false,
+		},
+		{
+			name: "when delivery tracking fails the notification is still sent",
+			args: args{
+				message: contracts.Message{
+					Value: `{
+						"uuid": "550e8400-e29b-41d4-a716-446655440000",
+						"title": "Order Confirmation",
+						"message": "Your order #12345 has been confirmed.",
+						"channels": {
+							"1": {
+								"id": 1,
+								"platform": "email",
+								"target_id": "user@example.com",
+								"group": "customers"
+							}
+						}
+					}`,
+					Headers: map[string]string{value.RetriesHeader: "0"},
+				},
+			},
+			setup: func(t *testing.T) *DispatcherUsecase {
+				repository := mocks.NewNotificationRepository(t)
+				deadLetterRepository := mocks.NewDeadLetterRepository(t)
+				deliveryRepository := mocks.NewDeliveryRepository(t)
+				ses := mocks.NewSESIface(t)
+				webhook := mocks.NewWebhook(t)
+				logger := mocks.NewLogger(t)
+				queue := mocks.NewQueue(t)
+
+				deliveryRepository.On("GetDeliveriesByUUID", mock.Anything).Return([]entity.Delivery{}, nil)
+				deliveryRepository.On("StartDelivery", "550e8400-e29b-41d4-a716-446655440000", 1).Return(errors.New("db error"))
+				deliveryRepository.On("FinishDelivery", "550e8400-e29b-41d4-a716-446655440000", 1, value.SuccessStatus, "", mock.Anything).Return(errors.New("db error"))
+				logger.On("Errorf", mock.Anything).Return()
+				ses.On("SendEmail", mock.Anything, mock.Anything).Return(nil)
+				repository.On("UpdateRecordStatus", mock.Anything, value.DeliveredStatus, mock.Anything).Return(nil)
+
+				return NewDispatcherUsecase(
+					repository,
+					deadLetterRepository,
+					deliveryRepository,
+					newTestRegistry(ses, webhook),
+					queue,
+					logger,
+				)
+			},
+			wantErr: false,
+		},
+		{
+			name: "the email provider that delivered is recorded on the delivery",
+			args: args{
+				message: contracts.Message{
+					Value: `{
+						"uuid": "550e8400-e29b-41d4-a716-446655440000",
+						"title": "Order Confirmation",
+						"message": "Your order #12345 has been confirmed.",
+						"channels": {
+							"1": {
+								"id": 1,
+								"platform": "email",
+								"target_id": "user@example.com",
+								"group": "customers"
+							}
+						}
+					}`,
+					Headers: map[string]string{value.RetriesHeader: "0"},
+				},
+			},
+			setup: func(t *testing.T) *DispatcherUsecase {
+				repository := mocks.NewNotificationRepository(t)
+				deadLetterRepository := mocks.NewDeadLetterRepository(t)
+				deliveryRepository := mocks.NewDeliveryRepository(t)
+				email := mocks.NewEmailRouter(t)
+				webhook := mocks.NewWebhook(t)
+				logger := mocks.NewLogger(t)
+				queue := mocks.NewQueue(t)
+
+				deliveryRepository.On("GetDeliveriesByUUID", mock.Anything).Return([]entity.Delivery{}, nil)
+				deliveryRepository.On("StartDelivery", "550e8400-e29b-41d4-a716-446655440000", 1).Return(nil)
+				deliveryRepository.On("FinishDelivery", "550e8400-e29b-41d4-a716-446655440000", 1, value.SuccessStatus, "", value.SMTPEmailProvider).Return(nil)
+				email.On("SendEmailVia", mock.Anything, mock.Anything).Return(value.SMTPEmailProvider, nil)
+				repository.On("UpdateRecordStatus", mock.Anything, value.DeliveredStatus, mock.Anything).Return(nil)
+
+				return NewDispatcherUsecase(
+					repository,
+					deadLetterRepository,
+					deliveryRepository,
+					newTestRegistry(email, webhook),
+					queue,
+					logger,
+				)
+			},
+			wantErr: false,
+		},
+		{
+			name: "when a channel fails only that channel is retried",
+			args: args{
+				message: contracts.Message{
+					Value: `{
+						"uuid": "550e8400-e29b-41d4-a716-446655440000",
+						"title": "Order Confirmation",
+						"message": "Your order #12345 has been confirmed.",
+						"channels": {
+							"1": {
+								"id": 1,
+								"platform": "email",
+								"target_id": "user@example.com",
+								"group": "customers"
+							},
+							"2": {
+								"id": 2,
+								"platform": "slack",
+								"target_id": "webhook_url",
+								"group": "customers"
+							}
+						}
+					}`,
+					Headers: map[string]string{value.RetriesHeader: "0"},
+				},
+			},
+			setup: func(t *testing.T) *DispatcherUsecase {
+				repository := mocks.NewNotificationRepository(t)
+				deadLetterRepository := mocks.NewDeadLetterRepository(t)
+				deliveryRepository := mocks.NewDeliveryRepository(t)
+				ses := mocks.NewSESIface(t)
+				webhook := mocks.NewWebhook(t)
+				logger := mocks.NewLogger(t)
+				queue := mocks.NewQueue(t)
+
+				deliveryRepository.On("GetDeliveriesByUUID", mock.Anything).Return([]entity.Delivery{}, nil)
+				deliveryRepository.On("StartDelivery", mock.Anything, mock.Anything).Return(nil)
+				deliveryRepository.On("FinishDelivery", mock.Anything, 1, value.SuccessStatus, "", mock.Anything).Return(nil)
+				deliveryRepository.On("FinishDelivery", mock.Anything, 2, value.ErrorStatus, mock.Anything, mock.Anything).Return(nil)
+				ses.On("SendEmail", mock.Anything, mock.Anything).Return(nil)
+				webhook.On("Post", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return(&contracts.HTTPResponse{
+						StatusCode: 500,
+						Close: func() error {
+							return nil
+						},
+					}, nil)
+				logger.On("Infof", mock.Anything).Return()
+				queue.On("Produce", value.GetRetryTiers()[0].Topic, mock.MatchedBy(func(message contracts.Message) bool {
+					var notification entity.Notification
+					if err := json.Unmarshal([]byte(message.Value), &notification); err != nil {
+						return false
+					}
+					_, hasSlack := notification.Channels[2]
+					return len(notification.Channels) == 1 && hasSlack &&
+						message.Key == "550e8400-e29b-41d4-a716-446655440000" &&
+						message.Headers[value.RetriesHeader] == "1" &&
+						message.Headers[value.NotBeforeHeader] != ""
+				})).Return(nil)
+				repository.On("UpdateRecordStatus", mock.Anything, value.RetryingStatus, mock.Anything).Return(nil)
+
+				return NewDispatcherUsecase(
+					repository,
+					deadLetterRepository,
+					deliveryRepository,
+					newTestRegistry(ses, webhook),
+					queue,
+					logger,
+				)
+			},
+			wantErr: false,
+		},
+		{
+			name: "when a channel was already delivered it is skipped",
+			args: args{
+				message: contracts.Message{
+					Value: `{
+						"uuid": "550e8400-e29b-41d4-a716-446655440000",
+						"title": "Order Confirmation",
+						"message": "Your order #12345 has been confirmed.",
+						"channels": {
+							"1": {
+								"id": 1,
+								"platform": "email",
+								"target_id": "user@example.com",
+								"group": "customers"
+							},
+							"2": {
+								"id": 2,
+								"platform": "slack",
+								"target_id": "webhook_url",
+								"group": "customers"
+							}
+						}
+					}`,
+					Headers: map[string]string{value.RetriesHeader: "0"},
+				},
+			},
+			setup: func(t *testing.T) *DispatcherUsecase {
+				repository := mocks.NewNotificationRepository(t)
+				deadLetterRepository := mocks.NewDeadLetterRepository(t)
+				deliveryRepository := mocks.NewDeliveryRepository(t)
+				ses := mocks.NewSESIface(t)
+				webhook := mocks.NewWebhook(t)
+				logger := mocks.NewLogger(t)
+				queue := mocks.NewQueue(t)
+
+				deliveryRepository.On("GetDeliveriesByUUID", mock.Anything).Return([]entity.Delivery{
+					{ChannelID: 1, Status: value.SuccessStatus},
+					{ChannelID: 2, Status: value.ErrorStatus},
+				}, nil)
+				deliveryRepository.On("StartDelivery", mock.Anything, 2).Return(nil)
+				deliveryRepository.On("FinishDelivery", mock.Anything, 2, value.SuccessStatus, "", mock.Anything).Return(nil)
+				webhook.On("Post", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return(&contracts.HTTPResponse{
+						StatusCode: 200,
+						Close: func() error {
+							return nil
+						},
+					}, nil)
+				repository.On("UpdateRecordStatus", mock.Anything, value.DeliveredStatus, mock.Anything).Return(nil)
+
+				return NewDispatcherUsecase(
+					repository,
+					deadLetterRepository,
+					deliveryRepository,
+					newTestRegistry(ses, webhook),
+					queue,
+					logger,
+				)
+			},
+			wantErr: false,
+		},
+		{
+			name: "when a channel times out the other channels are still sent",
+			args: args{
+				message: contracts.Message{
+					Value: `{
+						"id": 123,
+						"uuid": "550e8400-e29b-41d4-a716-446655440000",
+						"title": "Order Confirmation",
+						"message": "Your order #12345 has been confirmed.",
+						"channels": {
+							"1": {
+								"id": 1,
+								"platform": "email",
+								"target_id": "user@example.com",
+								"group": "customers"
+							},
+							"2": {
+								"id": 2,
+								"platform": "slack",
+								"target_id": "webhook_url",
+								"group": "customers"
+							}
+						},
+						"event": {
+							"name": "OrderPlaced",
+							"currency": "BRL",
+							"requester": "system",
+							"receiver": "user",
+							"category": "ecommerce",
+							"timestamp": 1716720000,
+							"cost_cents": 5000
+						}
+					}`,
+					Headers: map[string]string{value.RetriesHeader: "0"},
+				},
+			},
+			setup: func(t *testing.T) *DispatcherUsecase {
+				repository := mocks.NewNotificationRepository(t)
+				deadLetterRepository := mocks.NewDeadLetterRepository(t)
+				deliveryRepository := mocks.NewDeliveryRepository(t)
+				ses := mocks.NewSESIface(t)
+				webhook := mocks.NewWebhook(t)
+				logger := mocks.NewLogger(t)
+				queue := mocks.NewQueue(t)
+
+				viper.Set("DISPATCHER_CHANNEL_TIMEOUT", 50*time.Millisecond)
+				t.Cleanup(func() { viper.Set("DISPATCHER_CHANNEL_TIMEOUT", nil) })
+
+				deliveryRepository.On("GetDeliveriesByUUID", mock.Anything).Return([]entity.Delivery{}, nil)
+				deliveryRepository.On("StartDelivery", mock.Anything, mock.Anything).Return(nil)
+				deliveryRepository.On("FinishDelivery", mock.Anything, 1, value.SuccessStatus, "", mock.Anything).Return(nil)
+				deliveryRepository.On("FinishDelivery", mock.Anything, 2, value.ErrorStatus, mock.Anything, mock.Anything).Return(nil)
+				ses.On("SendEmail", mock.Anything, mock.Anything).Return(nil)
+				webhook.On("Post", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Run(func(args mock.Arguments) {
+						<-args.Get(0).(context.Context).Done()
+					}).
+					Return(nil, context.DeadlineExceeded)
+				logger.On("Infof", mock.Anything).Return()
+				queue.On("Produce", value.GetRetryTiers()[0].Topic, mock.MatchedBy(func(message contracts.Message) bool {
+					var notification entity.Notification
+					if err := json.Unmarshal([]byte(message.Value), &notification); err != nil {
+						return false
+					}
+					_, retried := notification.Channels[2]
+					return len(notification.Channels) == 1 && retried
+				})).Return(nil)
+				repository.On("UpdateRecordStatus", mock.Anything, value.RetryingStatus, mock.Anything).Return(nil)
+
+				return NewDispatcherUsecase(
+					repository,
+					deadLetterRepository,
+					deliveryRepository,
+					newTestRegistry(ses, webhook),
+					queue,
+					logger,
+				)
+			},
+			wantErr: false,
+		},
+		{
+			name: "when the notification expired it is dropped",
+			args: args{
+				message: contracts.Message{
+					Value: fmt.Sprintf(`{
+						"uuid": "550e8400-e29b-41d4-a716-446655440000",
+						"channels": {
+							"1": {
+								"id": 1,
+								"platform": "email",
+								"target_id": "user@example.com"
+							}
+						},
+						"expires_at": %q
+					}`, time.Now().Add(-time.Minute).Format(time.RFC3339)),
+					Headers: map[string]string{value.RetriesHeader: "1"},
+				},
+			},
+			setup: func(t *testing.T) *DispatcherUsecase {
+				repository := mocks.NewNotificationRepository(t)
+				deadLetterRepository := mocks.NewDeadLetterRepository(t)
+				deliveryRepository := mocks.NewDeliveryRepository(t)
+				ses := mocks.NewSESIface(t)
+				webhook := mocks.NewWebhook(t)
+				logger := mocks.NewLogger(t)
+				queue := mocks.NewQueue(t)
+
+				logger.On("Infof", mock.Anything).Return()
+				repository.On("UpdateRecordStatus", "550e8400-e29b-41d4-a716-446655440000", value.ExpiredStatus, int64(1)).Return(nil)
+				return NewDispatcherUsecase(
+					repository,
+					deadLetterRepository,
+					deliveryRepository,
+					newTestRegistry(ses, webhook),
+					queue,
+					logger,
+				)
+			},
+			wantErr: false,
+		},
+		{
+			name: "when the next retry would be after expiry it is dropped",
+			args: args{
+				message: contracts.Message{
+					Value: fmt.Sprintf(`{
+						"uuid": "550e8400-e29b-41d4-a716-446655440000",
+						"channels": {
+							"1": {
+								"id": 1,
+								"platform": "email",
+								"target_id": "user@example.com"
+							}
+						},
+						"expires_at": %q
+					}`, time.Now().Add(10*time.Second).Format(time.RFC3339)),
+					Headers: map[string]string{value.RetriesHeader: "0"},
+				},
+			},
+			setup: func(t *testing.T) *DispatcherUsecase {
+				repository := mocks.NewNotificationRepository(t)
+				deadLetterRepository := mocks.NewDeadLetterRepository(t)
+				deliveryRepository := mocks.NewDeliveryRepository(t)
+				ses := mocks.NewSESIface(t)
+				webhook := mocks.NewWebhook(t)
+				logger := mocks.NewLogger(t)
+				queue := mocks.NewQueue(t)
+
+				deliveryRepository.On("GetDeliveriesByUUID", mock.Anything).Return([]entity.Delivery{}, nil)
+				deliveryRepository.On("StartDelivery", "550e8400-e29b-41d4-a716-446655440000", 1).Return(nil)
+				deliveryRepository.On("FinishDelivery", "550e8400-e29b-41d4-a716-446655440000", 1, value.ErrorStatus, mock.Anything, mock.Anything).Return(nil)
+				ses.On("SendEmail", mock.Anything, mock.Anything).Return(errors.New("ses error"))
+				logger.On("Infof", mock.Anything).Return()
+				repository.On("UpdateRecordStatus", "550e8400-e29b-41d4-a716-446655440000", value.ExpiredStatus, int64(0)).Return(nil)
+				return NewDispatcherUsecase(
+					repository,
+					deadLetterRepository,
+					deliveryRepository,
+					newTestRegistry(ses, webhook),
+					queue,
+					logger,
+				)
+			},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			usecase := tt.setup(t)
-			err := usecase.Execute(tt.args.message)
+			err := usecase.Execute(context.Background(), tt.args.message)
 			if tt.wantErr {
 				assert.Error(t, err)
 				return
@@ -406,3 +1179,150 @@ func TestDispatcherUsecase_Execute(t *testing.T) {
 		})
 	}
 }
+
+func newTestRegistry(ses contracts.SESIface, webhook contracts.Webhook) *provider.Registry {
+	return provider.NewRegistry(
+		provider.NewEmailProvider(ses),
+		provider.NewSlackProvider(webhook),
+		provider.NewDiscordProvider(webhook),
+		provider.NewTeamsProvider(webhook),
+	)
+}
+
+func TestDispatcherUsecase_ExecuteStopsWaitingOnCancel(t *testing.T) {
+	repository := mocks.NewNotificationRepository(t)
+	deadLetterRepository := mocks.NewDeadLetterRepository(t)
+	deliveryRepository := mocks.NewDeliveryRepository(t)
+	ses := mocks.NewSESIface(t)
+	webhook := mocks.NewWebhook(t)
+	logger := mocks.NewLogger(t)
+	queue := mocks.NewQueue(t)
+
+	logger.On("Infof", mock.Anything).Return()
+
+	usecase := NewDispatcherUsecase(
+		repository,
+		deadLetterRepository,
+		deliveryRepository,
+		newTestRegistry(ses, webhook),
+		queue,
+		logger,
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	notBefore := time.Now().Add(time.Hour).Unix()
+	err := usecase.Execute(ctx, contracts.Message{
+		Value: `{
+			"uuid": "550e8400-e29b-41d4-a716-446655440000",
+			"channels": {
+				"1": {
+					"id": 1,
+					"platform": "email",
+					"target_id": "user@example.com"
+				}
+			}
+		}`,
+		Headers: map[string]string{
+			value.RetriesHeader:   "1",
+			value.NotBeforeHeader: fmt.Sprint(notBefore),
+		},
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestDispatcherUsecase_ExecuteStopsWaitingWhenStopping(t *testing.T) {
+	repository := mocks.NewNotificationRepository(t)
+	deadLetterRepository := mocks.NewDeadLetterRepository(t)
+	deliveryRepository := mocks.NewDeliveryRepository(t)
+	ses := mocks.NewSESIface(t)
+	webhook := mocks.NewWebhook(t)
+	logger := mocks.NewLogger(t)
+	queue := mocks.NewQueue(t)
+
+	logger.On("Infof", mock.Anything).Return()
+
+	usecase := NewDispatcherUsecase(
+		repository,
+		deadLetterRepository,
+		deliveryRepository,
+		newTestRegistry(ses, webhook),
+		queue,
+		logger,
+	)
+
+	// The consumer stopped fetching but the drain timeout is not over yet.
+	stopping := make(chan struct{})
+	close(stopping)
+	ctx := contracts.WithStopping(context.Background(), stopping)
+
+	notBefore := time.Now().Add(time.Hour).Unix()
+	err := usecase.Execute(ctx, contracts.Message{
+		Value: `{
+			"uuid": "550e8400-e29b-41d4-a716-446655440000",
+			"channels": {
+				"1": {
+					"id": 1,
+					"platform": "email",
+					"target_id": "user@example.com"
+				}
+			}
+		}`,
+		Headers: map[string]string{
+			value.RetriesHeader:   "1",
+			value.NotBeforeHeader: fmt.Sprint(notBefore),
+		},
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestDispatcherUsecase_ExecuteLeavesCutOffDeliveries(t *testing.T) {
+	repository := mocks.NewNotificationRepository(t)
+	deadLetterRepository := mocks.NewDeadLetterRepository(t)
+	deliveryRepository := mocks.NewDeliveryRepository(t)
+	ses := mocks.NewSESIface(t)
+	webhook := mocks.NewWebhook(t)
+	logger := mocks.NewLogger(t)
+	queue := mocks.NewQueue(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	deliveryRepository.On("GetDeliveriesByUUID", mock.Anything).Return([]entity.Delivery{}, nil)
+	deliveryRepository.On("StartDelivery", "550e8400-e29b-41d4-a716-446655440000", 1).Return(nil)
+	deliveryRepository.On("FinishDelivery", "550e8400-e29b-41d4-a716-446655440000", 1, value.ErrorStatus, mock.Anything, mock.Anything).Return(nil)
+	// The send hangs until the drain timeout cancels it.
+	ses.On("SendEmail", mock.Anything, mock.Anything).Return(func(ctx context.Context, email *entity.Email) error {
+		cancel()
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	usecase := NewDispatcherUsecase(
+		repository,
+		deadLetterRepository,
+		deliveryRepository,
+		newTestRegistry(ses, webhook),
+		queue,
+		logger,
+	)
+
+	err := usecase.Execute(ctx, contracts.Message{
+		Value: `{
+			"uuid": "550e8400-e29b-41d4-a716-446655440000",
+			"channels": {
+				"1": {
+					"id": 1,
+					"platform": "email",
+					"target_id": "user@example.com"
+				}
+			}
+		}`,
+		Headers: map[string]string{value.RetriesHeader: "0"},
+	})
+
+	// Nothing is requeued: the message is left uncommitted and redelivered.
+	assert.ErrorIs(t, err, context.Canceled)
+}
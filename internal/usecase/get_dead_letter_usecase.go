@@ -0,0 +1,26 @@
+package usecase
+
+import (
+	"github.com/gurodrigues-dev/notifier-app/internal/domain/repository"
+	"github.com/gurodrigues-dev/notifier-app/internal/entity"
+	"github.com/gurodrigues-dev/notifier-app/internal/infra/contracts"
+)
+
+type GetDeadLetterUsecase struct {
+	deadLetterRepository repository.DeadLetterRepository
+	logger               contracts.Logger
+}
+
+func NewGetDeadLetterUsecase(
+	deadLetterRepository repository.DeadLetterRepository,
+	logger contracts.Logger,
+) *GetDeadLetterUsecase {
+	return &GetDeadLetterUsecase{
+		deadLetterRepository: deadLetterRepository,
+		logger:               logger,
+	}
+}
+
+func (gdu *GetDeadLetterUsecase) GetDeadLetter(id string) (*entity.DeadLetter, error) {
+	return gdu.deadLetterRepository.GetDeadLetterByID(id)
+}
@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gurodrigues-dev/notifier-app/internal/entity"
+	"github.com/gurodrigues-dev/notifier-app/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDeadLetterUsecase_GetDeadLetter(t *testing.T) {
+	type args struct {
+		id string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		setup   func(t *testing.T) *GetDeadLetterUsecase
+		wantErr bool
+	}{
+		{
+			name: "there is to return success",
+			args: args{
+				id: "1",
+			},
+			setup: func(t *testing.T) *GetDeadLetterUsecase {
+				repository := mocks.NewDeadLetterRepository(t)
+				logger := mocks.NewLogger(t)
+				repository.On("GetDeadLetterByID", "1").Return(&entity.DeadLetter{ID: 1}, nil)
+				return NewGetDeadLetterUsecase(
+					repository,
+					logger,
+				)
+			},
+			wantErr: false,
+		},
+		{
+			name: "there is to return db error",
+			args: args{
+				id: "1",
+			},
+			setup: func(t *testing.T) *GetDeadLetterUsecase {
+				repository := mocks.NewDeadLetterRepository(t)
+				logger := mocks.NewLogger(t)
+				repository.On("GetDeadLetterByID", "1").Return(nil, errors.New("db error"))
+				return NewGetDeadLetterUsecase(
+					repository,
+					logger,
+				)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usecase := tt.setup(t)
+			_, err := usecase.GetDeadLetter(tt.args.id)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package usecase
+
+import (
+	"github.com/gurodrigues-dev/notifier-app/internal/domain/repository"
+	"github.com/gurodrigues-dev/notifier-app/internal/infra/contracts"
+	"github.com/gurodrigues-dev/notifier-app/internal/value"
+)
+
+type GetNotificationStatusUsecase struct {
+	notificationRepository repository.NotificationRepository
+	deliveryRepository     repository.DeliveryRepository
+	logger                 contracts.Logger
+}
+
+func NewGetNotificationStatusUsecase(
+	notificationRepository repository.NotificationRepository,
+	deliveryRepository repository.DeliveryRepository,
+	logger contracts.Logger,
+) *GetNotificationStatusUsecase {
+	return &GetNotificationStatusUsecase{
+		notificationRepository: notificationRepository,
+		deliveryRepository:     deliveryRepository,
+		logger:                 logger,
+	}
+}
+
+func (gsu *GetNotificationStatusUsecase) GetNotificationStatus(uuid string) (*value.NotificationStatusOutput, error) {
+	record, err := gsu.notificationRepository.GetRecordByUUID(uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries, err := gsu.deliveryRepository.GetDeliveriesByUUID(uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	return &value.NotificationStatusOutput{
+		UUID:       record.UUID,
+		Status:     record.Status,
+		Retries:    record.Retries,
+		AcceptedAt: record.CreatedAt,
+		UpdatedAt:  record.UpdatedAt,
+		Channels:   deliveries,
+	}, nil
+}
@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gurodrigues-dev/notifier-app/internal/entity"
+	"github.com/gurodrigues-dev/notifier-app/internal/value"
+	"github.com/gurodrigues-dev/notifier-app/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetNotificationStatusUsecase_GetNotificationStatus(t *testing.T) {
+	type args struct {
+		uuid string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		setup   func(t *testing.T) *GetNotificationStatusUsecase
+		wantErr bool
+	}{
+		{
+			name: "there is to return success",
+			args: args{
+				uuid: "5d91f580-030d-4c5d-b3a6-8383f5829bd3",
+			},
+			setup: func(t *testing.T) *GetNotificationStatusUsecase {
+				notificationRepository := mocks.NewNotificationRepository(t)
+				deliveryRepository := mocks.NewDeliveryRepository(t)
+				logger := mocks.NewLogger(t)
+				notificationRepository.On("GetRecordByUUID", "5d91f580-030d-4c5d-b3a6-8383f5829bd3").Return(&entity.NotificationRecord{
+					UUID:   "5d91f580-030d-4c5d-b3a6-8383f5829bd3",
+					Status: value.RetryingStatus,
+				}, nil)
+				deliveryRepository.On("GetDeliveriesByUUID", "5d91f580-030d-4c5d-b3a6-8383f5829bd3").Return([]entity.Delivery{
+					{ChannelID: 1, Status: value.SuccessStatus},
+					{ChannelID: 2, Status: value.ErrorStatus},
+				}, nil)
+				return NewGetNotificationStatusUsecase(
+					notificationRepository,
+					deliveryRepository,
+					logger,
+				)
+			},
+			wantErr: false,
+		},
+		{
+			name: "there is to return notification db error",
+			args: args{
+				uuid: "5d91f580-030d-4c5d-b3a6-8383f5829bd3",
+			},
+			setup: func(t *testing.T) *GetNotificationStatusUsecase {
+				notificationRepository := mocks.NewNotificationRepository(t)
+				deliveryRepository := mocks.NewDeliveryRepository(t)
+				logger := mocks.NewLogger(t)
+				notificationRepository.On("GetRecordByUUID", "5d91f580-030d-4c5d-b3a6-8383f5829bd3").Return(nil, errors.New("db error"))
+				return NewGetNotificationStatusUsecase(
+					notificationRepository,
+					deliveryRepository,
+					logger,
+				)
+			},
+			wantErr: true,
+		},
+		{
+			name: "there is to return delivery db error",
+			args: args{
+				uuid: "5d91f580-030d-4c5d-b3a6-8383f5829bd3",
+			},
+			setup: func(t *testing.T) *GetNotificationStatusUsecase {
+				notificationRepository := mocks.NewNotificationRepository(t)
+				deliveryRepository := mocks.NewDeliveryRepository(t)
+				logger := mocks.NewLogger(t)
+				notificationRepository.On("GetRecordByUUID", "5d91f580-030d-4c5d-b3a6-8383f5829bd3").Return(&entity.NotificationRecord{}, nil)
+				deliveryRepository.On("GetDeliveriesByUUID", "5d91f580-030d-4c5d-b3a6-8383f5829bd3").Return(nil, errors.New("db error"))
+				return NewGetNotificationStatusUsecase(
+					notificationRepository,
+					deliveryRepository,
+					logger,
+				)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usecase := tt.setup(t)
+			_, err := usecase.GetNotificationStatus(tt.args.uuid)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package usecase
+
+import (
+	"github.com/gurodrigues-dev/notifier-app/internal/domain/repository"
+	"github.com/gurodrigues-dev/notifier-app/internal/entity"
+	"github.com/gurodrigues-dev/notifier-app/internal/infra/contracts"
+)
+
+type ListDeadLettersUsecase struct {
+	deadLetterRepository repository.DeadLetterRepository
+	logger               contracts.Logger
+}
+
+func NewListDeadLettersUsecase(
+	deadLetterRepository repository.DeadLetterRepository,
+	logger contracts.Logger,
+) *ListDeadLettersUsecase {
+	return &ListDeadLettersUsecase{
+		deadLetterRepository: deadLetterRepository,
+		logger:               logger,
+	}
+}
+
+func (ldu *ListDeadLettersUsecase) ListDeadLetters(limit, offset int) ([]entity.DeadLetter, error) {
+	return ldu.deadLetterRepository.ListDeadLetters(limit, offset)
+}
@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gurodrigues-dev/notifier-app/internal/entity"
+	"github.com/gurodrigues-dev/notifier-app/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListDeadLettersUsecase_ListDeadLetters(t *testing.T) {
+	type args struct {
+		limit  int
+		offset int
+	}
+	tests := []struct {
+		name    string
+		args    args
+		setup   func(t *testing.T) *ListDeadLettersUsecase
+		wantErr bool
+	}{
+		{
+			name: "there is to return success",
+			args: args{
+				limit:  50,
+				offset: 0,
+			},
+			setup: func(t *testing.T) *ListDeadLettersUsecase {
+				repository := mocks.NewDeadLetterRepository(t)
+				logger := mocks.NewLogger(t)
+				repository.On("ListDeadLetters", 50, 0).Return([]entity.DeadLetter{{ID: 1}}, nil)
+				return NewListDeadLettersUsecase(
+					repository,
+					logger,
+				)
+			},
+			wantErr: false,
+		},
+		{
+			name: "there is to return db error",
+			args: args{
+				limit:  50,
+				offset: 0,
+			},
+			setup: func(t *testing.T) *ListDeadLettersUsecase {
+				repository := mocks.NewDeadLetterRepository(t)
+				logger := mocks.NewLogger(t)
+				repository.On("ListDeadLetters", 50, 0).Return(nil, errors.New("db error"))
+				return NewListDeadLettersUsecase(
+					repository,
+					logger,
+				)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usecase := tt.setup(t)
+			_, err := usecase.ListDeadLetters(tt.args.limit, tt.args.offset)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
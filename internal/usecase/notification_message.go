@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gurodrigues-dev/notifier-app/internal/entity"
+	"github.com/gurodrigues-dev/notifier-app/internal/infra/contracts"
+	"github.com/gurodrigues-dev/notifier-app/internal/value"
+)
+
+// newNotificationMessage builds the queue message of a notification. It is
+// keyed by UUID so every attempt of a notification lands on the same
+// partition, and its retry state travels in headers next to the trace ID.
+func newNotificationMessage(notification *entity.Notification, body []byte, traceID string) contracts.Message {
+	headers := map[string]string{
+		value.RetriesHeader:       strconv.FormatInt(notification.Retries, 10),
+		value.TraceIDHeader:       traceID,
+		value.SchemaVersionHeader: value.NotificationSchemaVersion,
+	}
+
+	if notification.NotBefore != 0 {
+		headers[value.NotBeforeHeader] = strconv.FormatInt(notification.NotBefore, 10)
+	}
+
+	return contracts.Message{
+		Key:     notification.UUID,
+		Value:   string(body),
+		Headers: headers,
+	}
+}
+
+// newNotificationOutbox builds the record of a notification being accepted and
+// the outbox row that publishes it. A sendAt in the future schedules it: the
+// record starts as scheduled and the relay holds the row until then.
+func newNotificationOutbox(notification *entity.Notification, body []byte, sendAt *time.Time) (*entity.NotificationRecord, *entity.OutboxMessage, error) {
+	message := newNotificationMessage(notification, body, uuid.NewString())
+	headers, err := json.Marshal(message.Headers)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	status, availableAt := value.AcceptedStatus, time.Now()
+	if sendAt != nil && sendAt.After(availableAt) {
+		status, availableAt = value.ScheduledStatus, *sendAt
+	}
+
+	record := &entity.NotificationRecord{
+		UUID:   notification.UUID,
+		Body:   body,
+		Status: status,
+	}
+
+	outbox := &entity.OutboxMessage{
+		Topic:       value.GetTopic(),
+		Key:         message.Key,
+		Payload:     message.Value,
+		Headers:     string(headers),
+		AvailableAt: availableAt,
+	}
+
+	return record, outbox, nil
+}
+
+// readNotificationHeaders restores the retry state of a notification from the
+// headers of its queue message. Missing headers leave the zero values.
+func readNotificationHeaders(notification *entity.Notification, headers map[string]string) error {
+	if retries, ok := headers[value.RetriesHeader]; ok {
+		parsed, err := strconv.ParseInt(retries, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid %s header: %w", value.RetriesHeader, err)
+		}
+		notification.Retries = parsed
+	}
+
+	if notBefore, ok := headers[value.NotBeforeHeader]; ok {
+		parsed, err := strconv.ParseInt(notBefore, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid %s header: %w", value.NotBeforeHeader, err)
+		}
+		notification.NotBefore = parsed
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gurodrigues-dev/notifier-app/internal/domain/repository"
+	"github.com/gurodrigues-dev/notifier-app/internal/entity"
+	"github.com/gurodrigues-dev/notifier-app/internal/infra/contracts"
+	"github.com/gurodrigues-dev/notifier-app/internal/value"
+	"github.com/gurodrigues-dev/notifier-app/pkg/stringcommon"
+)
+
+var (
+	ErrDeadLetterNotRedrivable   = errors.New("dead letter payload is not a notification")
+	ErrDeadLetterAlreadyRedriven = errors.New("dead letter was already redriven")
+)
+
+type RedriveDeadLetterUsecase struct {
+	deadLetterRepository repository.DeadLetterRepository
+	queue                contracts.Queue
+	logger               contracts.Logger
+}
+
+func NewRedriveDeadLetterUsecase(
+	deadLetterRepository repository.DeadLetterRepository,
+	queue contracts.Queue,
+	logger contracts.Logger,
+) *RedriveDeadLetterUsecase {
+	return &RedriveDeadLetterUsecase{
+		deadLetterRepository: deadLetterRepository,
+		queue:                queue,
+		logger:               logger,
+	}
+}
+
+// RedriveDeadLetter sends the dead letter back to the main topic with its
+// retry state cleared, so it goes through the whole retry cycle again. A dead
+// letter is re-driven once: it is marked before it is published, and the mark
+// is released when publishing fails so the re-drive can be retried.
+func (rdu *RedriveDeadLetterUsecase) RedriveDeadLetter(id string) error {
+	deadLetter, err := rdu.deadLetterRepository.GetDeadLetterByID(id)
+	if err != nil {
+		return err
+	}
+
+	if deadLetter.RedrivenAt != nil {
+		return ErrDeadLetterAlreadyRedriven
+	}
+
+	var notification *entity.Notification
+	err = json.Unmarshal([]byte(deadLetter.Payload), &notification)
+	if err != nil || notification == nil {
+		return ErrDeadLetterNotRedrivable
+	}
+
+	notification.Retries = 0
+	notification.NotBefore = 0
+	notification.Errors = nil
+
+	serializedMessage, err := stringcommon.SerializeToJSON(notification)
+	if err != nil {
+		return err
+	}
+
+	err = rdu.deadLetterRepository.MarkRedriven(deadLetter.ID, time.Now())
+	if errors.Is(err, repository.ErrAlreadyRedriven) {
+		return ErrDeadLetterAlreadyRedriven
+	}
+	if err != nil {
+		return err
+	}
+
+	rdu.logger.Infof(fmt.Sprintf("redriving dead letter %d", deadLetter.ID))
+	err = rdu.queue.Produce(value.GetTopic(), newNotificationMessage(notification, serializedMessage, uuid.NewString()))
+	if err != nil {
+		unmarkErr := rdu.deadLetterRepository.UnmarkRedriven(deadLetter.ID)
+		if unmarkErr != nil {
+			rdu.logger.Errorf(fmt.Sprintf("error releasing dead letter %d after a failed redrive: %v", deadLetter.ID, unmarkErr))
+		}
+		return err
+	}
+
+	return nil
+}
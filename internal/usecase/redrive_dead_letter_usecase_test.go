@@ -0,0 +1,170 @@
+package usecase
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gurodrigues-dev/notifier-app/internal/domain/repository"
+	"github.com/gurodrigues-dev/notifier-app/internal/entity"
+	"github.com/gurodrigues-dev/notifier-app/internal/infra/contracts"
+	"github.com/gurodrigues-dev/notifier-app/internal/value"
+	"github.com/gurodrigues-dev/notifier-app/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRedriveDeadLetterUsecase_RedriveDeadLetter(t *testing.T) {
+	type args struct {
+		id string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		setup   func(t *testing.T) *RedriveDeadLetterUsecase
+		wantErr bool
+	}{
+		{
+			name: "there is to return success",
+			args: args{
+				id: "1",
+			},
+			setup: func(t *testing.T) *RedriveDeadLetterUsecase {
+				repository := mocks.NewDeadLetterRepository(t)
+				queue := mocks.NewQueue(t)
+				logger := mocks.NewLogger(t)
+				repository.On("GetDeadLetterByID", "1").Return(&entity.DeadLetter{
+					ID:      1,
+					Payload: `{"uuid": "550e8400-e29b-41d4-a716-446655440000", "errors": ["email error"]}`,
+				}, nil)
+				logger.On("Infof", mock.Anything).Return()
+				queue.On("Produce", value.GetTopic(), mock.MatchedBy(func(message contracts.Message) bool {
+					return !strings.Contains(message.Value, "email error") &&
+						message.Headers[value.RetriesHeader] == "0"
+				})).Return(nil)
+				repository.On("MarkRedriven", 1, mock.Anything).Return(nil)
+				return NewRedriveDeadLetterUsecase(
+					repository,
+					queue,
+					logger,
+				)
+			},
+			wantErr: false,
+		},
+		{
+			name: "there is to return db error",
+			args: args{
+				id: "1",
+			},
+			setup: func(t *testing.T) *RedriveDeadLetterUsecase {
+				repository := mocks.NewDeadLetterRepository(t)
+				queue := mocks.NewQueue(t)
+				logger := mocks.NewLogger(t)
+				repository.On("GetDeadLetterByID", "1").Return(nil, errors.New("db error"))
+				return NewRedriveDeadLetterUsecase(
+					repository,
+					queue,
+					logger,
+				)
+			},
+			wantErr: true,
+		},
+		{
+			name: "there is to return not redrivable payload",
+			args: args{
+				id: "1",
+			},
+			setup: func(t *testing.T) *RedriveDeadLetterUsecase {
+				repository := mocks.NewDeadLetterRepository(t)
+				queue := mocks.NewQueue(t)
+				logger := mocks.NewLogger(t)
+				repository.On("GetDeadLetterByID", "1").Return(&entity.DeadLetter{
+					ID:      1,
+					Payload: "not a json",
+				}, nil)
+				return NewRedriveDeadLetterUsecase(
+					repository,
+					queue,
+					logger,
+				)
+			},
+			wantErr: true,
+		},
+		{
+			name: "there is to return kafka error",
+			args: args{
+				id: "1",
+			},
+			setup: func(t *testing.T) *RedriveDeadLetterUsecase {
+				repository := mocks.NewDeadLetterRepository(t)
+				queue := mocks.NewQueue(t)
+				logger := mocks.NewLogger(t)
+				repository.On("GetDeadLetterByID", "1").Return(&entity.DeadLetter{
+					ID:      1,
+					Payload: `{"uuid": "550e8400-e29b-41d4-a716-446655440000"}`,
+				}, nil)
+				repository.On("MarkRedriven", 1, mock.Anything).Return(nil)
+				logger.On("Infof", mock.Anything).Return()
+				queue.On("Produce", value.GetTopic(), mock.Anything).Return(errors.New("kafka error"))
+				repository.On("UnmarkRedriven", 1).Return(nil)
+				return NewRedriveDeadLetterUsecase(
+					repository,
+					queue,
+					logger,
+				)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usecase := tt.setup(t)
+			err := usecase.RedriveDeadLetter(tt.args.id)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestRedriveDeadLetterUsecase_RedriveDeadLetterOnce(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(repository *mocks.DeadLetterRepository)
+	}{
+		{
+			name: "there is to return already redriven",
+			setup: func(repository *mocks.DeadLetterRepository) {
+				redrivenAt := time.Now()
+				repository.On("GetDeadLetterByID", "1").Return(&entity.DeadLetter{
+					ID:         1,
+					Payload:    `{"uuid": "550e8400-e29b-41d4-a716-446655440000"}`,
+					RedrivenAt: &redrivenAt,
+				}, nil)
+			},
+		},
+		{
+			name: "there is to return already redriven by a concurrent request",
+			setup: func(deadLetterRepository *mocks.DeadLetterRepository) {
+				deadLetterRepository.On("GetDeadLetterByID", "1").Return(&entity.DeadLetter{
+					ID:      1,
+					Payload: `{"uuid": "550e8400-e29b-41d4-a716-446655440000"}`,
+				}, nil)
+				deadLetterRepository.On("MarkRedriven", 1, mock.Anything).Return(repository.ErrAlreadyRedriven)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deadLetterRepository := mocks.NewDeadLetterRepository(t)
+			tt.setup(deadLetterRepository)
+
+			usecase := NewRedriveDeadLetterUsecase(deadLetterRepository, mocks.NewQueue(t), mocks.NewLogger(t))
+			err := usecase.RedriveDeadLetter("1")
+			assert.ErrorIs(t, err, ErrDeadLetterAlreadyRedriven)
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gurodrigues-dev/notifier-app/internal/domain/repository"
+	"github.com/gurodrigues-dev/notifier-app/internal/entity"
+	"github.com/gurodrigues-dev/notifier-app/internal/infra/contracts"
+	"github.com/gurodrigues-dev/notifier-app/internal/value"
+)
+
+type RelayOutboxUsecase struct {
+	outboxRepository       repository.OutboxRepository
+	notificationRepository repository.NotificationRepository
+	queue                  contracts.Queue
+	logger                 contracts.Logger
+}
+
+func NewRelayOutboxUsecase(
+	outboxRepository repository.OutboxRepository,
+	notificationRepository repository.NotificationRepository,
+	queue contracts.Queue,
+	logger contracts.Logger,
+) *RelayOutboxUsecase {
+	return &RelayOutboxUsecase{
+		outboxRepository:       outboxRepository,
+		notificationRepository: notificationRepository,
+		queue:                  queue,
+		logger:                 logger,
+	}
+}
+
+// Relay publishes up to limit pending outbox messages and returns how many
+// were sent. The messages of each topic are produced as one batch; when the
+// batch fails every message in it stays in the outbox and is claimed again
+// once its lease is over.
+func (rou *RelayOutboxUsecase) Relay(limit int, lease time.Duration) (int, error) {
+	messages, err := rou.outboxRepository.ClaimPending(limit, lease)
+	if err != nil {
+		return 0, err
+	}
+
+	var topics []string
+	batches := make(map[string]*outboxBatch)
+	for _, message := range messages {
+		var headers map[string]string
+		err := json.Unmarshal([]byte(message.Headers), &headers)
+		if err != nil {
+			rou.markFailed(message.ID, fmt.Errorf("invalid headers: %w", err))
+			continue
+		}
+
+		batch, ok := batches[message.Topic]
+		if !ok {
+			batch = &outboxBatch{}
+			batches[message.Topic] = batch
+			topics = append(topics, message.Topic)
+		}
+		batch.rows = append(batch.rows, message)
+		batch.messages = append(batch.messages, contracts.Message{
+			Key:     message.Key,
+			Value:   message.Payload,
+			Headers: headers,
+		})
+	}
+
+	sent := 0
+	for _, topic := range topics {
+		batch := batches[topic]
+		err := rou.queue.ProduceBatch(topic, batch.messages)
+		if err != nil {
+			for _, row := range batch.rows {
+				rou.markFailed(row.ID, err)
+			}
+			continue
+		}
+
+		for _, row := range batch.rows {
+			err := rou.outboxRepository.MarkSent(row.ID, time.Now())
+			if err != nil {
+				rou.logger.Errorf(fmt.Sprintf("error marking outbox message %d as sent: %v", row.ID, err))
+				continue
+			}
+			sent++
+
+			err = rou.notificationRepository.AdvanceRecordStatus(row.Key, []string{value.AcceptedStatus, value.ScheduledStatus}, value.QueuedStatus)
+			if err != nil {
+				rou.logger.Errorf(fmt.Sprintf("error updating notification %s status: %v", row.Key, err))
+			}
+		}
+	}
+
+	return sent, nil
+}
+
+// outboxBatch holds the claimed rows of a topic and the messages built from
+// them, in the same order.
+type outboxBatch struct {
+	rows     []entity.OutboxMessage
+	messages []contracts.Message
+}
+
+func (rou *RelayOutboxUsecase) markFailed(id int, cause error) {
+	rou.logger.Errorf(fmt.Sprintf("error relaying outbox message %d: %v", id, cause))
+
+	err := rou.outboxRepository.MarkFailed(id, cause.Error())
+	if err != nil {
+		rou.logger.Errorf(fmt.Sprintf("error marking outbox message %d as failed: %v", id, err))
+	}
+}
@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gurodrigues-dev/notifier-app/internal/entity"
+	"github.com/gurodrigues-dev/notifier-app/internal/infra/contracts"
+	"github.com/gurodrigues-dev/notifier-app/internal/value"
+	"github.com/gurodrigues-dev/notifier-app/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRelayOutboxUsecase_Relay(t *testing.T) {
+	type args struct {
+		limit int
+		lease time.Duration
+	}
+	tests := []struct {
+		name     string
+		args     args
+		setup    func(t *testing.T) *RelayOutboxUsecase
+		wantSent int
+		wantErr  bool
+	}{
+		{
+			name: "there is to return success",
+			args: args{
+				limit: 10,
+				lease: 30 * time.Second,
+			},
+			setup: func(t *testing.T) *RelayOutboxUsecase {
+				outboxRepository := mocks.NewOutboxRepository(t)
+				notificationRepository := mocks.NewNotificationRepository(t)
+				queue := mocks.NewQueue(t)
+				logger := mocks.NewLogger(t)
+
+				outboxRepository.On("ClaimPending", 10, 30*time.Second).Return([]entity.OutboxMessage{
+					{
+						ID:      1,
+						Topic:   "notifications",
+						Key:     "550e8400-e29b-41d4-a716-446655440000",
+						Payload: `{"uuid": "550e8400-e29b-41d4-a716-446655440000"}`,
+						Headers: `{"retries": "0", "trace-id": "trace"}`,
+					},
+				}, nil)
+				queue.On("ProduceBatch", "notifications", []contracts.Message{
+					{
+						Key:     "550e8400-e29b-41d4-a716-446655440000",
+						Value:   `{"uuid": "550e8400-e29b-41d4-a716-446655440000"}`,
+						Headers: map[string]string{value.RetriesHeader: "0", value.TraceIDHeader: "trace"},
+					},
+				}).Return(nil)
+				outboxRepository.On("MarkSent", 1, mock.Anything).Return(nil)
+				notificationRepository.On("AdvanceRecordStatus", "550e8400-e29b-41d4-a716-446655440000", []string{value.AcceptedStatus, value.ScheduledStatus}, value.QueuedStatus).Return(nil)
+
+				return NewRelayOutboxUsecase(
+					outboxRepository,
+					notificationRepository,
+					queue,
+					logger,
+				)
+			},
+			wantSent: 1,
+			wantErr:  false,
+		},
+		{
+			name: "there is to return kafka error kept in outbox",
+			args: args{
+				limit: 10,
+				lease: 30 * time.Second,
+			},
+			setup: func(t *testing.T) *RelayOutboxUsecase {
+				outboxRepository := mocks.NewOutboxRepository(t)
+				notificationRepository := mocks.NewNotificationRepository(t)
+				queue := mocks.NewQueue(t)
+				logger := mocks.NewLogger(t)
+
+				outboxRepository.On("ClaimPending", 10, 30*time.Second).Return([]entity.OutboxMessage{
+					{ID: 1, Topic: "notifications", Key: "1", Payload: "{}", Headers: "{}"},
+					{ID: 2, Topic: "notifications-dlq", Key: "2", Payload: "{}", Headers: "{}"},
+					{ID: 3, Topic: "notifications", Key: "3", Payload: "{}", Headers: "{}"},
+				}, nil)
+				queue.On("ProduceBatch", "notifications", mock.MatchedBy(func(messages []contracts.Message) bool {
+					return len(messages) == 2 && messages[0].Key == "1" && messages[1].Key == "3"
+				})).Return(errors.New("kafka error"))
+				queue.On("ProduceBatch", "notifications-dlq", mock.MatchedBy(func(messages []contracts.Message) bool {
+					return len(messages) == 1 && messages[0].Key == "2"
+				})).Return(nil)
+				logger.On("Errorf", mock.Anything).Return()
+				outboxRepository.On("MarkFailed", 1, "kafka error").Return(nil)
+				outboxRepository.On("MarkFailed", 3, "kafka error").Return(nil)
+				outboxRepository.On("MarkSent", 2, mock.Anything).Return(nil)
+				notificationRepository.On("AdvanceRecordStatus", "2", []string{value.AcceptedStatus, value.ScheduledStatus}, value.QueuedStatus).Return(nil)
+
+				return NewRelayOutboxUsecase(
+					outboxRepository,
+					notificationRepository,
+					queue,
+					logger,
+				)
+			},
+			wantSent: 1,
+			wantErr:  false,
+		},
+		{
+			name: "there is to return invalid headers kept in outbox",
+			args: args{
+				limit: 10,
+				lease: 30 * time.Second,
+			},
+			setup: func(t *testing.T) *RelayOutboxUsecase {
+				outboxRepository := mocks.NewOutboxRepository(t)
+				notificationRepository := mocks.NewNotificationRepository(t)
+				queue := mocks.NewQueue(t)
+				logger := mocks.NewLogger(t)
+
+				outboxRepository.On("ClaimPending", 10, 30*time.Second).Return([]entity.OutboxMessage{
+					{ID: 1, Topic: "notifications", Key: "1", Payload: "{}", Headers: "invalid"},
+					{ID: 2, Topic: "notifications", Key: "2", Payload: "{}", Headers: "{}"},
+				}, nil)
+				queue.On("ProduceBatch", "notifications", mock.MatchedBy(func(messages []contracts.Message) bool {
+					return len(messages) == 1 && messages[0].Key == "2"
+				})).Return(nil)
+				logger.On("Errorf", mock.Anything).Return()
+				outboxRepository.On("MarkFailed", 1, mock.Anything).Return(nil)
+				outboxRepository.On("MarkSent", 2, mock.Anything).Return(nil)
+				notificationRepository.On("AdvanceRecordStatus", "2", []string{value.AcceptedStatus, value.ScheduledStatus}, value.QueuedStatus).Return(nil)
+
+				return NewRelayOutboxUsecase(
+					outboxRepository,
+					notificationRepository,
+					queue,
+					logger,
+				)
+			},
+			wantSent: 1,
+			wantErr:  false,
+		},
+		{
+			name: "there is to return claim db error",
+			args: args{
+				limit: 10,
+				lease: 30 * time.Second,
+			},
+			setup: func(t *testing.T) *RelayOutboxUsecase {
+				outboxRepository := mocks.NewOutboxRepository(t)
+				notificationRepository := mocks.NewNotificationRepository(t)
+				queue := mocks.NewQueue(t)
+				logger := mocks.NewLogger(t)
+
+				outboxRepository.On("ClaimPending", 10, 30*time.Second).Return(nil, errors.New("db error"))
+
+				return NewRelayOutboxUsecase(
+					outboxRepository,
+					notificationRepository,
+					queue,
+					logger,
+				)
+			},
+			wantSent: 0,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usecase := tt.setup(t)
+			sent, err := usecase.Relay(tt.args.limit, tt.args.lease)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantSent, sent)
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gurodrigues-dev/notifier-app/internal/domain/repository"
+	"github.com/gurodrigues-dev/notifier-app/internal/entity"
+	"github.com/gurodrigues-dev/notifier-app/internal/infra/contracts"
+	"github.com/gurodrigues-dev/notifier-app/internal/value"
+)
+
+type RescheduleNotificationUsecase struct {
+	notificationRepository repository.NotificationRepository
+	logger                 contracts.Logger
+}
+
+func NewRescheduleNotificationUsecase(
+	notificationRepository repository.NotificationRepository,
+	logger contracts.Logger,
+) *RescheduleNotificationUsecase {
+	return &RescheduleNotificationUsecase{
+		notificationRepository: notificationRepository,
+		logger:                 logger,
+	}
+}
+
+var ErrSendAtInPast = errors.New("send_at must be in the future")
+
+// RescheduleNotification moves a scheduled notification to sendAt, as long
+// as the relay has not picked it up yet and it would not be past its expiry
+// by then.
+func (rsu *RescheduleNotificationUsecase) RescheduleNotification(uuid string, sendAt time.Time) error {
+	if !sendAt.After(time.Now()) {
+		return ErrSendAtInPast
+	}
+
+	record, err := rsu.notificationRepository.GetRecordByUUID(uuid)
+	if err != nil {
+		return err
+	}
+
+	if record.Status != value.ScheduledStatus {
+		return ErrNotificationNotScheduled
+	}
+
+	var notification entity.Notification
+	err = json.Unmarshal(record.Body, &notification)
+	if err != nil {
+		return fmt.Errorf("error reading notification %s: %w", uuid, err)
+	}
+
+	if notification.ExpiresAt != nil && !notification.ExpiresAt.After(sendAt) {
+		return ErrInvalidExpiry
+	}
+
+	err = rsu.notificationRepository.RescheduleRecord(uuid, sendAt)
+	if errors.Is(err, repository.ErrNotScheduled) {
+		return ErrNotificationNotScheduled
+	}
+
+	return err
+}
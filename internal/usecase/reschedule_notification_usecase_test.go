@@ -0,0 +1,152 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gurodrigues-dev/notifier-app/internal/domain/repository"
+	"github.com/gurodrigues-dev/notifier-app/internal/entity"
+	"github.com/gurodrigues-dev/notifier-app/internal/value"
+	"github.com/gurodrigues-dev/notifier-app/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRescheduleNotificationUsecase_RescheduleNotification(t *testing.T) {
+	sendAt := time.Now().Add(time.Hour)
+
+	type args struct {
+		uuid   string
+		sendAt time.Time
+	}
+	tests := []struct {
+		name    string
+		args    args
+		setup   func(t *testing.T) *RescheduleNotificationUsecase
+		wantErr error
+	}{
+		{
+			name: "there is to return success",
+			args: args{
+				uuid:   "5d91f580-030d-4c5d-b3a6-8383f5829bd3",
+				sendAt: sendAt,
+			},
+			setup: func(t *testing.T) *RescheduleNotificationUsecase {
+				notificationRepository := mocks.NewNotificationRepository(t)
+				logger := mocks.NewLogger(t)
+				notificationRepository.On("GetRecordByUUID", "5d91f580-030d-4c5d-b3a6-8383f5829bd3").Return(&entity.NotificationRecord{
+					UUID:   "5d91f580-030d-4c5d-b3a6-8383f5829bd3",
+					Body:   []byte(`{"uuid": "5d91f580-030d-4c5d-b3a6-8383f5829bd3"}`),
+					Status: value.ScheduledStatus,
+				}, nil)
+				notificationRepository.On("RescheduleRecord", "5d91f580-030d-4c5d-b3a6-8383f5829bd3", sendAt).Return(nil)
+				return NewRescheduleNotificationUsecase(
+					notificationRepository,
+					logger,
+				)
+			},
+			wantErr: nil,
+		},
+		{
+			name: "there is to return send at in past",
+			args: args{
+				uuid:   "5d91f580-030d-4c5d-b3a6-8383f5829bd3",
+				sendAt: time.Now().Add(-time.Minute),
+			},
+			setup: func(t *testing.T) *RescheduleNotificationUsecase {
+				return NewRescheduleNotificationUsecase(
+					mocks.NewNotificationRepository(t),
+					mocks.NewLogger(t),
+				)
+			},
+			wantErr: ErrSendAtInPast,
+		},
+		{
+			name: "there is to return not scheduled",
+			args: args{
+				uuid:   "5d91f580-030d-4c5d-b3a6-8383f5829bd3",
+				sendAt: sendAt,
+			},
+			setup: func(t *testing.T) *RescheduleNotificationUsecase {
+				notificationRepository := mocks.NewNotificationRepository(t)
+				logger := mocks.NewLogger(t)
+				notificationRepository.On("GetRecordByUUID", "5d91f580-030d-4c5d-b3a6-8383f5829bd3").Return(&entity.NotificationRecord{
+					UUID:   "5d91f580-030d-4c5d-b3a6-8383f5829bd3",
+					Status: value.DeliveredStatus,
+				}, nil)
+				return NewRescheduleNotificationUsecase(
+					notificationRepository,
+					logger,
+				)
+			},
+			wantErr: ErrNotificationNotScheduled,
+		},
+		{
+			name: "there is to return send at after expiry",
+			args: args{
+				uuid:   "5d91f580-030d-4c5d-b3a6-8383f5829bd3",
+				sendAt: sendAt,
+			},
+			setup: func(t *testing.T) *RescheduleNotificationUsecase {
+				notificationRepository := mocks.NewNotificationRepository(t)
+				logger := mocks.NewLogger(t)
+				notificationRepository.On("GetRecordByUUID", "5d91f580-030d-4c5d-b3a6-8383f5829bd3").Return(&entity.NotificationRecord{
+					UUID:   "5d91f580-030d-4c5d-b3a6-8383f5829bd3",
+					Body:   []byte(`{"uuid": "5d91f580-030d-4c5d-b3a6-8383f5829bd3", "expires_at": "` + sendAt.Add(-time.Minute).Format(time.RFC3339) + `"}`),
+					Status: value.ScheduledStatus,
+				}, nil)
+				return NewRescheduleNotificationUsecase(
+					notificationRepository,
+					logger,
+				)
+			},
+			wantErr: ErrInvalidExpiry,
+		},
+		{
+			name: "there is to return claimed by relay",
+			args: args{
+				uuid:   "5d91f580-030d-4c5d-b3a6-8383f5829bd3",
+				sendAt: sendAt,
+			},
+			setup: func(t *testing.T) *RescheduleNotificationUsecase {
+				notificationRepository := mocks.NewNotificationRepository(t)
+				logger := mocks.NewLogger(t)
+				notificationRepository.On("GetRecordByUUID", "5d91f580-030d-4c5d-b3a6-8383f5829bd3").Return(&entity.NotificationRecord{
+					UUID:   "5d91f580-030d-4c5d-b3a6-8383f5829bd3",
+					Body:   []byte(`{"uuid": "5d91f580-030d-4c5d-b3a6-8383f5829bd3"}`),
+					Status: value.ScheduledStatus,
+				}, nil)
+				notificationRepository.On("RescheduleRecord", "5d91f580-030d-4c5d-b3a6-8383f5829bd3", sendAt).Return(repository.ErrNotScheduled)
+				return NewRescheduleNotificationUsecase(
+					notificationRepository,
+					logger,
+				)
+			},
+			wantErr: ErrNotificationNotScheduled,
+		},
+		{
+			name: "there is to return db error",
+			args: args{
+				uuid:   "5d91f580-030d-4c5d-b3a6-8383f5829bd3",
+				sendAt: sendAt,
+			},
+			setup: func(t *testing.T) *RescheduleNotificationUsecase {
+				notificationRepository := mocks.NewNotificationRepository(t)
+				logger := mocks.NewLogger(t)
+				notificationRepository.On("GetRecordByUUID", "5d91f580-030d-4c5d-b3a6-8383f5829bd3").Return(nil, errors.New("db error"))
+				return NewRescheduleNotificationUsecase(
+					notificationRepository,
+					logger,
+				)
+			},
+			wantErr: errors.New("db error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usecase := tt.setup(t)
+			err := usecase.RescheduleNotification(tt.args.uuid, tt.args.sendAt)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/gurodrigues-dev/notifier-app/internal/domain/repository"
+	"github.com/gurodrigues-dev/notifier-app/internal/entity"
+	"github.com/gurodrigues-dev/notifier-app/internal/infra/contracts"
+)
+
+type StoreDeadLetterUsecase struct {
+	deadLetterRepository repository.DeadLetterRepository
+	logger               contracts.Logger
+}
+
+func NewStoreDeadLetterUsecase(
+	deadLetterRepository repository.DeadLetterRepository,
+	logger contracts.Logger,
+) *StoreDeadLetterUsecase {
+	return &StoreDeadLetterUsecase{
+		deadLetterRepository: deadLetterRepository,
+		logger:               logger,
+	}
+}
+
+// Execute stores a message of the dead-letter topic, so the dead letters the
+// consumer publishes when a handler exhausts its attempts can be listed and
+// re-driven like the ones the dispatcher stores itself. Those already carry
+// the id they were stored with and are skipped. An error is returned only
+// when the dead letter could not be stored, so it is retried until Postgres
+// is back.
+func (sdu *StoreDeadLetterUsecase) Execute(ctx context.Context, message contracts.Message) error {
+	var deadLetter *entity.DeadLetter
+	err := json.Unmarshal([]byte(message.Value), &deadLetter)
+	if err != nil || deadLetter == nil {
+		sdu.logger.Errorf(fmt.Sprintf("skipping dead letter %s that cannot be read: %v", message.Key, err))
+		return nil
+	}
+
+	if deadLetter.ID != 0 {
+		return nil
+	}
+
+	err = sdu.deadLetterRepository.CreateDeadLetter(deadLetter)
+	if errors.Is(err, repository.ErrDuplicateKey) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error storing dead letter %s: %w", deadLetter.UUID, err)
+	}
+
+	sdu.logger.Infof(fmt.Sprintf("stored dead letter %d of %s", deadLetter.ID, deadLetter.UUID))
+	return nil
+}
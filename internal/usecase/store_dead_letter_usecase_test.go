@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gurodrigues-dev/notifier-app/internal/domain/repository"
+	"github.com/gurodrigues-dev/notifier-app/internal/entity"
+	"github.com/gurodrigues-dev/notifier-app/internal/infra/contracts"
+	"github.com/gurodrigues-dev/notifier-app/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestStoreDeadLetterUsecase_Execute(t *testing.T) {
+	type args struct {
+		message contracts.Message
+	}
+	tests := []struct {
+		name    string
+		args    args
+		setup   func(t *testing.T) *StoreDeadLetterUsecase
+		wantErr bool
+	}{
+		{
+			name: "there is to return success",
+			args: args{
+				message: contracts.Message{
+					Key:   "550e8400-e29b-41d4-a716-446655440000",
+					Value: `{"uuid": "550e8400-e29b-41d4-a716-446655440000", "payload": "{}", "errors": ["db error"], "attempts": 8}`,
+				},
+			},
+			setup: func(t *testing.T) *StoreDeadLetterUsecase {
+				repository := mocks.NewDeadLetterRepository(t)
+				logger := mocks.NewLogger(t)
+				repository.On("CreateDeadLetter", mock.MatchedBy(func(deadLetter *entity.DeadLetter) bool {
+					return deadLetter.UUID == "550e8400-e29b-41d4-a716-446655440000" &&
+						deadLetter.Attempts == 8
+				})).Return(nil)
+				logger.On("Infof", mock.Anything).Return()
+				return NewStoreDeadLetterUsecase(repository, logger)
+			},
+			wantErr: false,
+		},
+		{
+			name: "there is to skip a dead letter already stored",
+			args: args{
+				message: contracts.Message{
+					Key:   "550e8400-e29b-41d4-a716-446655440000",
+					Value: `{"id": 1, "uuid": "550e8400-e29b-41d4-a716-446655440000", "attempts": 4}`,
+				},
+			},
+			setup: func(t *testing.T) *StoreDeadLetterUsecase {
+				return NewStoreDeadLetterUsecase(mocks.NewDeadLetterRepository(t), mocks.NewLogger(t))
+			},
+			wantErr: false,
+		},
+		{
+			name: "there is to skip a duplicate dead letter",
+			args: args{
+				message: contracts.Message{
+					Key:   "550e8400-e29b-41d4-a716-446655440000",
+					Value: `{"uuid": "550e8400-e29b-41d4-a716-446655440000", "attempts": 8}`,
+				},
+			},
+			setup: func(t *testing.T) *StoreDeadLetterUsecase {
+				deadLetterRepository := mocks.NewDeadLetterRepository(t)
+				deadLetterRepository.On("CreateDeadLetter", mock.Anything).Return(repository.ErrDuplicateKey)
+				return NewStoreDeadLetterUsecase(deadLetterRepository, mocks.NewLogger(t))
+			},
+			wantErr: false,
+		},
+		{
+			name: "there is to skip an unreadable dead letter",
+			args: args{
+				message: contracts.Message{
+					Key:   "550e8400-e29b-41d4-a716-446655440000",
+					Value: `not json`,
+				},
+			},
+			setup: func(t *testing.T) *StoreDeadLetterUsecase {
+				logger := mocks.NewLogger(t)
+				logger.On("Errorf", mock.Anything).Return()
+				return NewStoreDeadLetterUsecase(mocks.NewDeadLetterRepository(t), logger)
+			},
+			wantErr: false,
+		},
+		{
+			name: "there is to return db error",
+			args: args{
+				message: contracts.Message{
+					Key:   "550e8400-e29b-41d4-a716-446655440000",
+					Value: `{"uuid": "550e8400-e29b-41d4-a716-446655440000", "attempts": 8}`,
+				},
+			},
+			setup: func(t *testing.T) *StoreDeadLetterUsecase {
+				repository := mocks.NewDeadLetterRepository(t)
+				repository.On("CreateDeadLetter", mock.Anything).Return(errors.New("db error"))
+				return NewStoreDeadLetterUsecase(repository, mocks.NewLogger(t))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usecase := tt.setup(t)
+			err := usecase.Execute(context.Background(), tt.args.message)
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
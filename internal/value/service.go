@@ -1,16 +1,34 @@
 package value
 
 import (
+	"strings"
+	"time"
+
 	"github.com/gurodrigues-dev/notifier-app/internal/entity"
+	"github.com/gurodrigues-dev/notifier-app/pkg/stringcommon"
 	"github.com/spf13/viper"
 )
 
 const (
 	// platforms
 
-	EmailPlatform   = "email"
-	SlackPlatform   = "slack"
-	DiscordPlatform = "discord"
+	EmailPlatform    = "email"
+	SlackPlatform    = "slack"
+	DiscordPlatform  = "discord"
+	TeamsPlatform    = "teams"
+	TelegramPlatform = "telegram"
+	WebhookPlatform  = "webhook"
+
+	// email providers
+
+	SESEmailProvider  = "ses"
+	SMTPEmailProvider = "smtp"
+
+	// smtp tls modes
+
+	SMTPStartTLS    = "starttls"
+	SMTPImplicitTLS = "implicit"
+	SMTPNoTLS       = "none"
 
 	// event status
 
@@ -18,19 +36,92 @@ const (
 	ErrorStatus   = "error"
 	PendingStatus = "pending"
 
+	// notification status
+
+	AcceptedStatus  = "accepted"
+	QueuedStatus    = "queued"
+	RetryingStatus  = "retrying"
+	DeliveredStatus = "delivered"
+	FailedStatus    = "failed"
+	ScheduledStatus = "scheduled"
+	CancelledStatus = "cancelled"
+	ExpiredStatus   = "expired"
+
+	// batch item results
+
+	BatchAccepted  = "accepted"
+	BatchDuplicate = "duplicate"
+	BatchInvalid   = "invalid"
+
 	MaxRetries = 3
-)
 
-var (
-	Platforms = []string{EmailPlatform, SlackPlatform, DiscordPlatform}
+	// message headers
+
+	RetriesHeader       = "retries"
+	NotBeforeHeader     = "not-before"
+	TraceIDHeader       = "trace-id"
+	SchemaVersionHeader = "schema-version"
+
+	NotificationSchemaVersion = "1"
+
+	// signed webhooks
+
+	WebhookEnvelopeVersion = "1"
+	WebhookSignatureHeader = "X-Notifier-Signature"
+	WebhookTimestampHeader = "X-Notifier-Timestamp"
+
+	defaultDispatcherWorkers = 10
+	defaultChannelTimeout    = 10 * time.Second
+	defaultConsumerWorkers   = 8
+	defaultConsumerBuffer    = 64
+	defaultDrainTimeout      = 30 * time.Second
+	defaultHandlerAttempts   = 8
+	defaultBatchSize         = 100
+	defaultBatchTimeout      = 10 * time.Millisecond
+	defaultRequiredAcks      = "all"
+
+	defaultHTTPReadTimeout     = 10 * time.Second
+	defaultHTTPWriteTimeout    = 15 * time.Second
+	defaultHTTPIdleTimeout     = 60 * time.Second
+	defaultHTTPShutdownTimeout = 20 * time.Second
+
+	defaultOutboxPollInterval = time.Second
+	defaultOutboxBatchSize    = 100
+	defaultOutboxLease        = 30 * time.Second
+
+	defaultNotificationBatchMax = 100
+
+	defaultTelegramAPIURL = "https://api.telegram.org"
+
+	defaultEmailProvider = SESEmailProvider
+	defaultSMTPPort      = 587
+	defaultSMTPTLS       = SMTPStartTLS
+	defaultSMTPPoolSize  = 4
+	defaultSMTPTimeout   = 10 * time.Second
+
+	defaultEmailBreakerThreshold = 5
+	defaultEmailBreakerCooldown  = time.Minute
 )
 
+// RetryTier is a delay topic: messages produced to it are dispatched only
+// after Delay has passed since they were requeued.
+type RetryTier struct {
+	Topic string
+	Delay time.Duration
+}
+
 type NotificationInput struct {
-	UUID     string   `json:"uuid" validate:"required"`
-	Title    string   `json:"title" validate:"required"`
-	Message  string   `json:"message"`
-	Channels []string `json:"channels" validate:"required,min=1,max=20,dive,required"`
-	Event    Event    `json:"event" validate:"required"`
+	UUID      string     `json:"uuid" validate:"required"`
+	Title     string     `json:"title" validate:"required"`
+	Message   string     `json:"message"`
+	Channels  []string   `json:"channels" validate:"required,min=1,max=20,dive,required"`
+	Event     Event      `json:"event" validate:"required"`
+	SendAt    *time.Time `json:"send_at,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+type RescheduleInput struct {
+	SendAt time.Time `json:"send_at" validate:"required"`
 }
 
 type NotificationOutput struct {
@@ -40,6 +131,49 @@ type NotificationOutput struct {
 	Error string              `json:"error"`
 }
 
+// NotificationAccepted is returned when a notification is accepted, with
+// what a producer needs to correlate and poll it.
+type NotificationAccepted struct {
+	UUID       string     `json:"uuid"`
+	Channels   []int      `json:"channels"`
+	StatusURL  string     `json:"status_url"`
+	AcceptedAt time.Time  `json:"accepted_at"`
+	SendAt     *time.Time `json:"send_at,omitempty"`
+}
+
+type NotificationBatchInput struct {
+	Notifications []NotificationInput `json:"notifications" validate:"required,min=1"`
+}
+
+// NotificationBatchResult is the outcome of one notification of a batch, at
+// the same index it was sent. Error explains why an invalid one was rejected.
+type NotificationBatchResult struct {
+	Index      int        `json:"index"`
+	UUID       string     `json:"uuid"`
+	Status     string     `json:"status"`
+	Channels   []int      `json:"channels,omitempty"`
+	StatusURL  string     `json:"status_url,omitempty"`
+	AcceptedAt *time.Time `json:"accepted_at,omitempty"`
+	SendAt     *time.Time `json:"send_at,omitempty"`
+	Error      string     `json:"error,omitempty"`
+}
+
+type NotificationBatchOutput struct {
+	Accepted   int                       `json:"accepted"`
+	Duplicates int                       `json:"duplicates"`
+	Invalid    int                       `json:"invalid"`
+	Results    []NotificationBatchResult `json:"results"`
+}
+
+type NotificationStatusOutput struct {
+	UUID       string            `json:"uuid"`
+	Status     string            `json:"status"`
+	Retries    int64             `json:"retries"`
+	AcceptedAt time.Time         `json:"accepted_at"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+	Channels   []entity.Delivery `json:"channels"`
+}
+
 type Event struct {
 	Name      string `json:"name" validate:"required"`
 	Currency  string `json:"currency" validate:"required"`
@@ -53,3 +187,213 @@ type Event struct {
 func GetTopic() string {
 	return viper.GetString("KAFKA_TOPIC")
 }
+
+func GetDeadLetterTopic() string {
+	topic := viper.GetString("KAFKA_DLQ_TOPIC")
+	if stringcommon.Empty(topic) {
+		return GetTopic() + "-dlq"
+	}
+	return topic
+}
+
+// GetRetryTiers returns the delay topics used by the dispatcher, where the
+// tier at index N receives the notifications on their retry N+1.
+func GetRetryTiers() []RetryTier {
+	topic := GetTopic()
+	return []RetryTier{
+		{Topic: topic + "-retry-30s", Delay: 30 * time.Second},
+		{Topic: topic + "-retry-5m", Delay: 5 * time.Minute},
+		{Topic: topic + "-retry-30m", Delay: 30 * time.Minute},
+	}
+}
+
+// GetDispatcherWorkers returns how many channels of a single notification are
+// sent at the same time.
+func GetDispatcherWorkers() int {
+	return intOr("DISPATCHER_WORKERS", defaultDispatcherWorkers)
+}
+
+// GetChannelTimeout returns how long a single channel send may take before it
+// is cancelled and counted as failed.
+func GetChannelTimeout() time.Duration {
+	return durationOr("DISPATCHER_CHANNEL_TIMEOUT", defaultChannelTimeout)
+}
+
+// GetConsumerWorkers returns how many messages of a topic the consumer
+// handles at the same time. Messages with the same key never run in parallel.
+func GetConsumerWorkers() int {
+	return intOr("KAFKA_CONSUMER_WORKERS", defaultConsumerWorkers)
+}
+
+// GetConsumerBuffer returns how many messages each consumer worker holds
+// before the reader stops fetching from Kafka.
+func GetConsumerBuffer() int {
+	return intOr("KAFKA_CONSUMER_BUFFER", defaultConsumerBuffer)
+}
+
+// GetDrainTimeout returns how long a stopping consumer lets the messages in
+// flight finish before cancelling them.
+func GetDrainTimeout() time.Duration {
+	return durationOr("KAFKA_DRAIN_TIMEOUT", defaultDrainTimeout)
+}
+
+// GetHandlerAttempts returns how many times a consumer runs its handler on a
+// message before giving up and publishing it to the dead-letter topic.
+func GetHandlerAttempts() int {
+	return intOr("KAFKA_HANDLER_ATTEMPTS", defaultHandlerAttempts)
+}
+
+// KafkaProducer holds how messages are batched and acknowledged when they
+// are written to Kafka.
+type KafkaProducer struct {
+	BatchSize    int
+	BatchTimeout time.Duration
+	Compression  string
+	RequiredAcks string
+}
+
+// GetKafkaProducer returns the producer settings. Compression is one of
+// gzip, snappy, lz4 or zstd (none when empty) and RequiredAcks one of all,
+// one or none.
+func GetKafkaProducer() KafkaProducer {
+	requiredAcks := viper.GetString("KAFKA_REQUIRED_ACKS")
+	if stringcommon.Empty(requiredAcks) {
+		requiredAcks = defaultRequiredAcks
+	}
+
+	return KafkaProducer{
+		BatchSize:    intOr("KAFKA_BATCH_SIZE", defaultBatchSize),
+		BatchTimeout: durationOr("KAFKA_BATCH_TIMEOUT", defaultBatchTimeout),
+		Compression:  viper.GetString("KAFKA_COMPRESSION"),
+		RequiredAcks: requiredAcks,
+	}
+}
+
+// HTTPTimeouts holds the limits applied to the API server connections.
+type HTTPTimeouts struct {
+	Read     time.Duration
+	Write    time.Duration
+	Idle     time.Duration
+	Shutdown time.Duration
+}
+
+// GetHTTPTimeouts returns the API server timeouts, where Shutdown is how long
+// in-flight requests may run once the server starts to stop.
+func GetHTTPTimeouts() HTTPTimeouts {
+	return HTTPTimeouts{
+		Read:     durationOr("HTTP_READ_TIMEOUT", defaultHTTPReadTimeout),
+		Write:    durationOr("HTTP_WRITE_TIMEOUT", defaultHTTPWriteTimeout),
+		Idle:     durationOr("HTTP_IDLE_TIMEOUT", defaultHTTPIdleTimeout),
+		Shutdown: durationOr("HTTP_SHUTDOWN_TIMEOUT", defaultHTTPShutdownTimeout),
+	}
+}
+
+// Outbox holds how the outbox relay polls for pending messages: every
+// PollInterval it claims up to BatchSize of them for Lease.
+type Outbox struct {
+	PollInterval time.Duration
+	BatchSize    int
+	Lease        time.Duration
+}
+
+func GetOutbox() Outbox {
+	return Outbox{
+		PollInterval: durationOr("OUTBOX_POLL_INTERVAL", defaultOutboxPollInterval),
+		BatchSize:    intOr("OUTBOX_BATCH_SIZE", defaultOutboxBatchSize),
+		Lease:        durationOr("OUTBOX_LEASE", defaultOutboxLease),
+	}
+}
+
+// GetNotificationBatchMax returns how many notifications a single batch
+// request may carry.
+func GetNotificationBatchMax() int {
+	return intOr("NOTIFICATION_BATCH_MAX", defaultNotificationBatchMax)
+}
+
+// GetTelegramAPIURL returns the base URL of the Telegram Bot API.
+func GetTelegramAPIURL() string {
+	url := viper.GetString("TELEGRAM_API_URL")
+	if stringcommon.Empty(url) {
+		return defaultTelegramAPIURL
+	}
+	return url
+}
+
+// GetEmailProviders returns the email senders to use, in the order they are
+// tried, from a comma separated EMAIL_PROVIDER such as "ses,smtp".
+func GetEmailProviders() []string {
+	var providers []string
+	for _, provider := range strings.Split(viper.GetString("EMAIL_PROVIDER"), ",") {
+		provider = strings.TrimSpace(provider)
+		if !stringcommon.Empty(provider) {
+			providers = append(providers, provider)
+		}
+	}
+
+	if len(providers) == 0 {
+		return []string{defaultEmailProvider}
+	}
+	return providers
+}
+
+// EmailBreaker holds when an email provider is taken out of rotation: after
+// Threshold consecutive failures it is skipped for Cooldown, then tried again.
+type EmailBreaker struct {
+	Threshold int
+	Cooldown  time.Duration
+}
+
+func GetEmailBreaker() EmailBreaker {
+	return EmailBreaker{
+		Threshold: intOr("EMAIL_BREAKER_THRESHOLD", defaultEmailBreakerThreshold),
+		Cooldown:  durationOr("EMAIL_BREAKER_COOLDOWN", defaultEmailBreakerCooldown),
+	}
+}
+
+// SMTP holds how emails are sent through an SMTP relay. TLS is starttls,
+// implicit or none, and PoolSize is how many idle connections are kept open
+// between sends.
+type SMTP struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	TLS      string
+	PoolSize int
+	Timeout  time.Duration
+}
+
+func GetSMTP() SMTP {
+	tls := viper.GetString("SMTP_TLS")
+	if stringcommon.Empty(tls) {
+		tls = defaultSMTPTLS
+	}
+
+	return SMTP{
+		Host:     viper.GetString("SMTP_HOST"),
+		Port:     intOr("SMTP_PORT", defaultSMTPPort),
+		Username: viper.GetString("SMTP_USERNAME"),
+		Password: viper.GetString("SMTP_PASSWORD"),
+		From:     viper.GetString("SMTP_FROM"),
+		TLS:      tls,
+		PoolSize: intOr("SMTP_POOL_SIZE", defaultSMTPPoolSize),
+		Timeout:  durationOr("SMTP_TIMEOUT", defaultSMTPTimeout),
+	}
+}
+
+func durationOr(key string, fallback time.Duration) time.Duration {
+	duration := viper.GetDuration(key)
+	if duration <= 0 {
+		return fallback
+	}
+	return duration
+}
+
+func intOr(key string, fallback int) int {
+	value := viper.GetInt(key)
+	if value <= 0 {
+		return fallback
+	}
+	return value
+}
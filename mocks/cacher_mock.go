@@ -13,6 +13,42 @@ type Cacher struct {
 	mock.Mock
 }
 
+// Close provides a mock function with no fields
+func (_m *Cacher) Close() error {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Close")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Delete provides a mock function with given fields: key
+func (_m *Cacher) Delete(key string) error {
+	ret := _m.Called(key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Expire provides a mock function with given fields: key, expiration
 func (_m *Cacher) Expire(key string, expiration time.Duration) (bool, error) {
 	ret := _m.Called(key, expiration)
@@ -87,6 +123,34 @@ func (_m *Cacher) Set(key string, value interface{}, expiration time.Duration) e
 	return r0
 }
 
+// SetNX provides a mock function with given fields: key, value, expiration
+func (_m *Cacher) SetNX(key string, value interface{}, expiration time.Duration) (bool, error) {
+	ret := _m.Called(key, value, expiration)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetNX")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, interface{}, time.Duration) (bool, error)); ok {
+		return rf(key, value, expiration)
+	}
+	if rf, ok := ret.Get(0).(func(string, interface{}, time.Duration) bool); ok {
+		r0 = rf(key, value, expiration)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, interface{}, time.Duration) error); ok {
+		r1 = rf(key, value, expiration)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewCacher creates a new instance of Cacher. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewCacher(t interface {
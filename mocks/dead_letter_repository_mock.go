@@ -0,0 +1,173 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	entity "github.com/gurodrigues-dev/notifier-app/internal/entity"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// DeadLetterRepository is an autogenerated mock type for the DeadLetterRepository type
+type DeadLetterRepository struct {
+	mock.Mock
+}
+
+// CreateDeadLetter provides a mock function with given fields: deadLetter
+func (_m *DeadLetterRepository) CreateDeadLetter(deadLetter *entity.DeadLetter) error {
+	ret := _m.Called(deadLetter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateDeadLetter")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entity.DeadLetter) error); ok {
+		r0 = rf(deadLetter)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetDeadLetterByID provides a mock function with given fields: id
+func (_m *DeadLetterRepository) GetDeadLetterByID(id string) (*entity.DeadLetter, error) {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDeadLetterByID")
+	}
+
+	var r0 *entity.DeadLetter
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*entity.DeadLetter, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(string) *entity.DeadLetter); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.DeadLetter)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPendingDeadLetter provides a mock function with given fields: uuid, attempts
+func (_m *DeadLetterRepository) GetPendingDeadLetter(uuid string, attempts int64) (*entity.DeadLetter, error) {
+	ret := _m.Called(uuid, attempts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPendingDeadLetter")
+	}
+
+	var r0 *entity.DeadLetter
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, int64) (*entity.DeadLetter, error)); ok {
+		return rf(uuid, attempts)
+	}
+	if rf, ok := ret.Get(0).(func(string, int64) *entity.DeadLetter); ok {
+		r0 = rf(uuid, attempts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.DeadLetter)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, int64) error); ok {
+		r1 = rf(uuid, attempts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListDeadLetters provides a mock function with given fields: limit, offset
+func (_m *DeadLetterRepository) ListDeadLetters(limit int, offset int) ([]entity.DeadLetter, error) {
+	ret := _m.Called(limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListDeadLetters")
+	}
+
+	var r0 []entity.DeadLetter
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, int) ([]entity.DeadLetter, error)); ok {
+		return rf(limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(int, int) []entity.DeadLetter); ok {
+		r0 = rf(limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.DeadLetter)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int, int) error); ok {
+		r1 = rf(limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkRedriven provides a mock function with given fields: id, redrivenAt
+func (_m *DeadLetterRepository) MarkRedriven(id int, redrivenAt time.Time) error {
+	ret := _m.Called(id, redrivenAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkRedriven")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, time.Time) error); ok {
+		r0 = rf(id, redrivenAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UnmarkRedriven provides a mock function with given fields: id
+func (_m *DeadLetterRepository) UnmarkRedriven(id int) error {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UnmarkRedriven")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewDeadLetterRepository creates a new instance of DeadLetterRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDeadLetterRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DeadLetterRepository {
+	mock := &DeadLetterRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
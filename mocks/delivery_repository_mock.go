@@ -0,0 +1,93 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	entity "github.com/gurodrigues-dev/notifier-app/internal/entity"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// DeliveryRepository is an autogenerated mock type for the DeliveryRepository type
+type DeliveryRepository struct {
+	mock.Mock
+}
+
+// FinishDelivery provides a mock function with given fields: uuid, channelID, status, lastError, provider
+func (_m *DeliveryRepository) FinishDelivery(uuid string, channelID int, status string, lastError string, provider string) error {
+	ret := _m.Called(uuid, channelID, status, lastError, provider)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FinishDelivery")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, int, string, string, string) error); ok {
+		r0 = rf(uuid, channelID, status, lastError, provider)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetDeliveriesByUUID provides a mock function with given fields: uuid
+func (_m *DeliveryRepository) GetDeliveriesByUUID(uuid string) ([]entity.Delivery, error) {
+	ret := _m.Called(uuid)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDeliveriesByUUID")
+	}
+
+	var r0 []entity.Delivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]entity.Delivery, error)); ok {
+		return rf(uuid)
+	}
+	if rf, ok := ret.Get(0).(func(string) []entity.Delivery); ok {
+		r0 = rf(uuid)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Delivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(uuid)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StartDelivery provides a mock function with given fields: uuid, channelID
+func (_m *DeliveryRepository) StartDelivery(uuid string, channelID int) error {
+	ret := _m.Called(uuid, channelID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StartDelivery")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, int) error); ok {
+		r0 = rf(uuid, channelID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewDeliveryRepository creates a new instance of DeliveryRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDeliveryRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DeliveryRepository {
+	mock := &DeliveryRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -5,6 +5,8 @@ package mocks
 import (
 	entity "github.com/gurodrigues-dev/notifier-app/internal/entity"
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // NotificationRepository is an autogenerated mock type for the NotificationRepository type
@@ -12,6 +14,42 @@ type NotificationRepository struct {
 	mock.Mock
 }
 
+// AdvanceRecordStatus provides a mock function with given fields: uuid, from, to
+func (_m *NotificationRepository) AdvanceRecordStatus(uuid string, from []string, to string) error {
+	ret := _m.Called(uuid, from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AdvanceRecordStatus")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, []string, string) error); ok {
+		r0 = rf(uuid, from, to)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CancelScheduled provides a mock function with given fields: uuid
+func (_m *NotificationRepository) CancelScheduled(uuid string) error {
+	ret := _m.Called(uuid)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CancelScheduled")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(uuid)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CreateNotification provides a mock function with given fields: notification
 func (_m *NotificationRepository) CreateNotification(notification *entity.NotificationError) error {
 	ret := _m.Called(notification)
@@ -30,6 +68,54 @@ func (_m *NotificationRepository) CreateNotification(notification *entity.Notifi
 	return r0
 }
 
+// CreateRecordWithOutbox provides a mock function with given fields: record, message
+func (_m *NotificationRepository) CreateRecordWithOutbox(record *entity.NotificationRecord, message *entity.OutboxMessage) error {
+	ret := _m.Called(record, message)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateRecordWithOutbox")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entity.NotificationRecord, *entity.OutboxMessage) error); ok {
+		r0 = rf(record, message)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateRecordsWithOutbox provides a mock function with given fields: records, messages
+func (_m *NotificationRepository) CreateRecordsWithOutbox(records []*entity.NotificationRecord, messages []*entity.OutboxMessage) ([]string, error) {
+	ret := _m.Called(records, messages)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateRecordsWithOutbox")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func([]*entity.NotificationRecord, []*entity.OutboxMessage) ([]string, error)); ok {
+		return rf(records, messages)
+	}
+	if rf, ok := ret.Get(0).(func([]*entity.NotificationRecord, []*entity.OutboxMessage) []string); ok {
+		r0 = rf(records, messages)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func([]*entity.NotificationRecord, []*entity.OutboxMessage) error); ok {
+		r1 = rf(records, messages)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetNotificationByID provides a mock function with given fields: id
 func (_m *NotificationRepository) GetNotificationByID(id string) (*entity.NotificationError, error) {
 	ret := _m.Called(id)
@@ -60,6 +146,72 @@ func (_m *NotificationRepository) GetNotificationByID(id string) (*entity.Notifi
 	return r0, r1
 }
 
+// GetRecordByUUID provides a mock function with given fields: uuid
+func (_m *NotificationRepository) GetRecordByUUID(uuid string) (*entity.NotificationRecord, error) {
+	ret := _m.Called(uuid)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRecordByUUID")
+	}
+
+	var r0 *entity.NotificationRecord
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*entity.NotificationRecord, error)); ok {
+		return rf(uuid)
+	}
+	if rf, ok := ret.Get(0).(func(string) *entity.NotificationRecord); ok {
+		r0 = rf(uuid)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.NotificationRecord)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(uuid)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RescheduleRecord provides a mock function with given fields: uuid, sendAt
+func (_m *NotificationRepository) RescheduleRecord(uuid string, sendAt time.Time) error {
+	ret := _m.Called(uuid, sendAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RescheduleRecord")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, time.Time) error); ok {
+		r0 = rf(uuid, sendAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateRecordStatus provides a mock function with given fields: uuid, status, retries
+func (_m *NotificationRepository) UpdateRecordStatus(uuid string, status string, retries int64) error {
+	ret := _m.Called(uuid, status, retries)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateRecordStatus")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, int64) error); ok {
+		r0 = rf(uuid, status, retries)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewNotificationRepository creates a new instance of NotificationRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewNotificationRepository(t interface {
@@ -0,0 +1,95 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	entity "github.com/gurodrigues-dev/notifier-app/internal/entity"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// OutboxRepository is an autogenerated mock type for the OutboxRepository type
+type OutboxRepository struct {
+	mock.Mock
+}
+
+// ClaimPending provides a mock function with given fields: limit, lease
+func (_m *OutboxRepository) ClaimPending(limit int, lease time.Duration) ([]entity.OutboxMessage, error) {
+	ret := _m.Called(limit, lease)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimPending")
+	}
+
+	var r0 []entity.OutboxMessage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, time.Duration) ([]entity.OutboxMessage, error)); ok {
+		return rf(limit, lease)
+	}
+	if rf, ok := ret.Get(0).(func(int, time.Duration) []entity.OutboxMessage); ok {
+		r0 = rf(limit, lease)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.OutboxMessage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int, time.Duration) error); ok {
+		r1 = rf(limit, lease)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkFailed provides a mock function with given fields: id, lastError
+func (_m *OutboxRepository) MarkFailed(id int, lastError string) error {
+	ret := _m.Called(id, lastError)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkFailed")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, string) error); ok {
+		r0 = rf(id, lastError)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MarkSent provides a mock function with given fields: id, at
+func (_m *OutboxRepository) MarkSent(id int, at time.Time) error {
+	ret := _m.Called(id, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkSent")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, time.Time) error); ok {
+		r0 = rf(id, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewOutboxRepository creates a new instance of OutboxRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewOutboxRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *OutboxRepository {
+	mock := &OutboxRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -2,24 +2,47 @@
 
 package mocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	context "context"
+
+	contracts "github.com/gurodrigues-dev/notifier-app/internal/infra/contracts"
+	mock "github.com/stretchr/testify/mock"
+)
 
 // Queue is an autogenerated mock type for the Queue type
 type Queue struct {
 	mock.Mock
 }
 
-// Consumer provides a mock function with given fields: topic, group, handler
-func (_m *Queue) Consumer(topic string, group string, handler func(string)) error {
-	ret := _m.Called(topic, group, handler)
+// Close provides a mock function with no fields
+func (_m *Queue) Close() error {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Close")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Consumer provides a mock function with given fields: ctx, topic, group, handler
+func (_m *Queue) Consumer(ctx context.Context, topic string, group string, handler func(context.Context, contracts.Message) error) error {
+	ret := _m.Called(ctx, topic, group, handler)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Consumer")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, func(string)) error); ok {
-		r0 = rf(topic, group, handler)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, func(context.Context, contracts.Message) error) error); ok {
+		r0 = rf(ctx, topic, group, handler)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -28,7 +51,7 @@ func (_m *Queue) Consumer(topic string, group string, handler func(string)) erro
 }
 
 // Produce provides a mock function with given fields: topic, message
-func (_m *Queue) Produce(topic string, message string) error {
+func (_m *Queue) Produce(topic string, message contracts.Message) error {
 	ret := _m.Called(topic, message)
 
 	if len(ret) == 0 {
@@ -36,7 +59,7 @@ func (_m *Queue) Produce(topic string, message string) error {
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+	if rf, ok := ret.Get(0).(func(string, contracts.Message) error); ok {
 		r0 = rf(topic, message)
 	} else {
 		r0 = ret.Error(0)
@@ -45,6 +68,24 @@ func (_m *Queue) Produce(topic string, message string) error {
 	return r0
 }
 
+// ProduceBatch provides a mock function with given fields: topic, messages
+func (_m *Queue) ProduceBatch(topic string, messages []contracts.Message) error {
+	ret := _m.Called(topic, messages)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ProduceBatch")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, []contracts.Message) error); ok {
+		r0 = rf(topic, messages)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewQueue creates a new instance of Queue. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewQueue(t interface {
@@ -3,7 +3,10 @@
 package mocks
 
 import (
+	context "context"
+
 	entity "github.com/gurodrigues-dev/notifier-app/internal/entity"
+
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -12,17 +15,17 @@ type SESIface struct {
 	mock.Mock
 }
 
-// SendEmail provides a mock function with given fields: email
-func (_m *SESIface) SendEmail(email *entity.Email) error {
-	ret := _m.Called(email)
+// SendEmail provides a mock function with given fields: ctx, email
+func (_m *SESIface) SendEmail(ctx context.Context, email *entity.Email) error {
+	ret := _m.Called(ctx, email)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SendEmail")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*entity.Email) error); ok {
-		r0 = rf(email)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Email) error); ok {
+		r0 = rf(ctx, email)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -3,6 +3,7 @@
 package mocks
 
 import (
+	context "context"
 	io "io"
 
 	contracts "github.com/gurodrigues-dev/notifier-app/internal/infra/contracts"
@@ -15,9 +16,9 @@ type Webhook struct {
 	mock.Mock
 }
 
-// Post provides a mock function with given fields: url, contentType, body
-func (_m *Webhook) Post(url string, contentType string, body io.Reader) (*contracts.HTTPResponse, error) {
-	ret := _m.Called(url, contentType, body)
+// Post provides a mock function with given fields: ctx, url, contentType, body
+func (_m *Webhook) Post(ctx context.Context, url string, contentType string, body io.Reader) (*contracts.HTTPResponse, error) {
+	ret := _m.Called(ctx, url, contentType, body)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Post")
@@ -25,19 +26,49 @@ func (_m *Webhook) Post(url string, contentType string, body io.Reader) (*contra
 
 	var r0 *contracts.HTTPResponse
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, string, io.Reader) (*contracts.HTTPResponse, error)); ok {
-		return rf(url, contentType, body)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, io.Reader) (*contracts.HTTPResponse, error)); ok {
+		return rf(ctx, url, contentType, body)
 	}
-	if rf, ok := ret.Get(0).(func(string, string, io.Reader) *contracts.HTTPResponse); ok {
-		r0 = rf(url, contentType, body)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, io.Reader) *contracts.HTTPResponse); ok {
+		r0 = rf(ctx, url, contentType, body)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*contracts.HTTPResponse)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string, string, io.Reader) error); ok {
-		r1 = rf(url, contentType, body)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, io.Reader) error); ok {
+		r1 = rf(ctx, url, contentType, body)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PostWithHeaders provides a mock function with given fields: ctx, url, headers, body
+func (_m *Webhook) PostWithHeaders(ctx context.Context, url string, headers map[string]string, body io.Reader) (*contracts.HTTPResponse, error) {
+	ret := _m.Called(ctx, url, headers, body)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PostWithHeaders")
+	}
+
+	var r0 *contracts.HTTPResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, map[string]string, io.Reader) (*contracts.HTTPResponse, error)); ok {
+		return rf(ctx, url, headers, body)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, map[string]string, io.Reader) *contracts.HTTPResponse); ok {
+		r0 = rf(ctx, url, headers, body)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*contracts.HTTPResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, map[string]string, io.Reader) error); ok {
+		r1 = rf(ctx, url, headers, body)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -0,0 +1,62 @@
+package keyedpool
+
+import (
+	"hash/fnv"
+	"sync"
+)
+
+// Pool runs jobs on a fixed number of workers. Jobs submitted with the same
+// key always land on the same worker, so they run one at a time and in the
+// order they were submitted, while jobs with different keys run in parallel.
+type Pool struct {
+	queues []chan func()
+	wg     sync.WaitGroup
+}
+
+// New starts a pool with the given number of workers, each holding at most
+// buffer pending jobs. Submit blocks once a worker queue is full.
+func New(workers, buffer int) *Pool {
+	if workers <= 0 {
+		workers = 1
+	}
+	if buffer < 0 {
+		buffer = 0
+	}
+
+	p := &Pool{
+		queues: make([]chan func(), workers),
+	}
+
+	for i := range p.queues {
+		p.queues[i] = make(chan func(), buffer)
+		p.wg.Add(1)
+		go func(queue chan func()) {
+			defer p.wg.Done()
+			for job := range queue {
+				job()
+			}
+		}(p.queues[i])
+	}
+
+	return p
+}
+
+// Submit queues job on the worker that owns key, blocking while that worker
+// is full.
+func (p *Pool) Submit(key []byte, job func()) {
+	p.queues[p.worker(key)] <- job
+}
+
+// Close stops accepting jobs and waits for the queued ones to finish.
+func (p *Pool) Close() {
+	for _, queue := range p.queues {
+		close(queue)
+	}
+	p.wg.Wait()
+}
+
+func (p *Pool) worker(key []byte) int {
+	hash := fnv.New32a()
+	hash.Write(key)
+	return int(hash.Sum32() % uint32(len(p.queues)))
+}
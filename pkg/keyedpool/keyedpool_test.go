@@ -0,0 +1,85 @@
+package keyedpool
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPool_SubmitKeepsOrderPerKey(t *testing.T) {
+	pool := New(4, 2)
+
+	var (
+		mu   sync.Mutex
+		seen = make(map[string][]int)
+	)
+
+	keys := []string{"a", "b", "c", "d", "e"}
+	for i := 0; i < 100; i++ {
+		for _, key := range keys {
+			pool.Submit([]byte(key), func() {
+				mu.Lock()
+				seen[key] = append(seen[key], i)
+				mu.Unlock()
+			})
+		}
+	}
+	pool.Close()
+
+	for _, key := range keys {
+		assert.Len(t, seen[key], 100, key)
+		for i, got := range seen[key] {
+			assert.Equal(t, i, got, fmt.Sprintf("key %s out of order", key))
+		}
+	}
+}
+
+func TestPool_SubmitRunsKeysInParallel(t *testing.T) {
+	pool := New(8, 0)
+	defer pool.Close()
+
+	blocked := make(chan struct{})
+	done := make(chan struct{})
+
+	keys := []string{"a", "b"}
+	for pool.worker([]byte(keys[0])) == pool.worker([]byte(keys[1])) {
+		keys[1] += "b"
+	}
+
+	pool.Submit([]byte(keys[0]), func() { <-blocked })
+	pool.Submit([]byte(keys[1]), func() { close(done) })
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("job on a free worker was held back by a busy one")
+	}
+	close(blocked)
+}
+
+func TestPool_SubmitBlocksWhenFull(t *testing.T) {
+	pool := New(1, 1)
+	defer pool.Close()
+
+	blocked := make(chan struct{})
+	pool.Submit([]byte("a"), func() { <-blocked })
+	pool.Submit([]byte("a"), func() {})
+
+	submitted := make(chan struct{})
+	go func() {
+		pool.Submit([]byte("a"), func() {})
+		close(submitted)
+	}()
+
+	select {
+	case <-submitted:
+		t.Fatal("submit did not block on a full worker")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(blocked)
+	<-submitted
+}